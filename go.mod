@@ -2,6 +2,13 @@ module github.com/bogdan-deac/path-template
 
 go 1.23.0
 
-require gotest.tools/v3 v3.5.1
+require (
+	github.com/xeipuuv/gojsonschema v1.2.0
+	gotest.tools/v3 v3.5.1
+)
 
-require github.com/google/go-cmp v0.5.9 // indirect
+require (
+	github.com/google/go-cmp v0.5.9 // indirect
+	github.com/xeipuuv/gojsonpointer v0.0.0-20180127040702-4e3ac2762d5f // indirect
+	github.com/xeipuuv/gojsonreference v0.0.0-20180127040603-bd5ef7bd5415 // indirect
+)
@@ -0,0 +1,60 @@
+package path_template
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base32"
+	"strings"
+)
+
+// pseudonymLength is the length of the pseudonym of a run of letters and digits
+const pseudonymLength = 8
+
+// pseudonymEncoding encodes pseudonyms with lowercase letters and digits, valid in any literal
+var pseudonymEncoding = base32.NewEncoding("abcdefghijklmnopqrstuvwxyz234567").WithPadding(base32.NoPadding)
+
+// Anonymize replaces the letters and digits of the literals of a path template with pseudonyms
+// keyed with key, so failing templates can be shared in bug reports without leaking internal
+// URL structure. Each run of letters and digits becomes a run of 8 lowercase letters and digits,
+// other characters and percent-encodings are kept, so validity is preserved: an invalid template
+// stays invalid for the same reason. Operators and variable names are kept.
+// With the same key, equal literals get equal pseudonyms, across calls too, so pseudonyms are
+// linkable. Without the key they can't be reversed, key should be random and kept secret.
+// /internal/{id}/secret.txt -> /bupgtqgs/{id}/sthdpwcb.pg62cba5
+func Anonymize(template string, key []byte) string {
+	return mapLiterals(template, func(literal string) string {
+		return pseudonym(key, literal)
+	})
+}
+
+// pseudonym replaces each run of letters and digits of literal with an HMAC of the literal and
+// the position of the run
+func pseudonym(key []byte, literal string) string {
+	var sb strings.Builder
+	run := 0
+	for i := 0; i < len(literal); i++ {
+		c := literal[i]
+		switch {
+		case c == '%' && i+2 < len(literal):
+			// keep percent-encodings valid
+			sb.WriteString(literal[i : i+3])
+			i += 2
+		case isAlphanumeric(c):
+			for i+1 < len(literal) && isAlphanumeric(literal[i+1]) {
+				i++
+			}
+			mac := hmac.New(sha256.New, key)
+			mac.Write([]byte(literal))
+			mac.Write([]byte{0, byte(run >> 8), byte(run)})
+			sb.WriteString(pseudonymEncoding.EncodeToString(mac.Sum(nil))[:pseudonymLength])
+			run++
+		default:
+			sb.WriteByte(c)
+		}
+	}
+	return sb.String()
+}
+
+func isAlphanumeric(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9'
+}
@@ -0,0 +1,50 @@
+package path_template
+
+import (
+	"strings"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestAnonymize(t *testing.T) {
+	key := []byte("test key")
+	assert.Equal(t, Anonymize("/internal/{id}/secret.txt", key), "/bupgtqgs/{id}/sthdpwcb.pg62cba5")
+	// stable across calls and templates
+	assert.Equal(t, Anonymize("/internal/**", key), "/bupgtqgs/**")
+	assert.Equal(t, Anonymize("/a%2Fb/{x}-v1", key), "/ier7ur4c%2F4m77fn4b/{x}-7aeqil4g")
+	// keyed
+	assert.Assert(t, Anonymize("/internal/**", []byte("other key")) != Anonymize("/internal/**", key))
+
+	for _, template := range []string{
+		"/internal/{id}/secret.txt",
+		"/api/v1/{tenant=acme/*}/**.m3u8",
+		"/Users/42/",
+		"/a%2Fb=c",
+		// invalid ones stay invalid for the same reason
+		"/internal/{id}/{id}",
+		"/internal//x",
+		"/internal/x*",
+		"/internal/{id=a*}",
+		"/internal/*.ts/x",
+		"internal",
+		"/in ternal",
+	} {
+		anonymized := Anonymize(template, key)
+		for _, literal := range []string{"internal", "secret", "acme", "Users", "42", "ternal"} {
+			assert.Assert(t, !strings.Contains(anonymized, literal), anonymized)
+		}
+
+		_, err := ValidatePathTemplate(template, WithRedactValues())
+		_, anonymizedErr := ValidatePathTemplate(anonymized, WithRedactValues())
+		if err == nil {
+			assert.NilError(t, anonymizedErr, anonymized)
+		} else {
+			assert.Error(t, anonymizedErr, err.Error(), anonymized)
+		}
+
+		_, err = ValidatePathTemplate(template, WithStrictRFC3986())
+		_, anonymizedErr = ValidatePathTemplate(anonymized, WithStrictRFC3986())
+		assert.Equal(t, err == nil, anonymizedErr == nil, anonymized)
+	}
+}
@@ -0,0 +1,27 @@
+package path_template
+
+import (
+	"strings"
+	"unsafe"
+)
+
+// ValidatePathTemplateBytes is ValidatePathTemplate for a path template held in a byte slice,
+// as received from HTTP stacks. Valid path templates are validated without copying path, which
+// must not be modified during the call. The returned variable names and errors, as well as the
+// templates given to hooks, don't share memory with path.
+func ValidatePathTemplateBytes(path []byte, opts ...Option) ([]string, error) {
+	o := newOptions(opts)
+	if o.hooks.OnParseStart != nil || o.hooks.OnParseEnd != nil || o.traceContext != nil {
+		// hooks may keep the template
+		return ValidatePathTemplate(string(path), opts...)
+	}
+	variableNames, err := validatePathTemplate(unsafe.String(unsafe.SliceData(path), len(path)), o)
+	if err != nil {
+		// errors may quote the template, they are built again from a copy
+		return ValidatePathTemplate(string(path), opts...)
+	}
+	for i, name := range variableNames {
+		variableNames[i] = strings.Clone(name)
+	}
+	return variableNames, nil
+}
@@ -0,0 +1,35 @@
+package path_template
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestValidatePathTemplateBytes(t *testing.T) {
+	path := []byte("/a/{foo}/{bar=**}")
+	names, err := ValidatePathTemplateBytes(path)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, names, []string{"foo", "bar"})
+
+	// the variable names don't change with the byte slice
+	copy(path, "/a/{xyz}/{abc=**}")
+	assert.DeepEqual(t, names, []string{"foo", "bar"})
+
+	path = []byte("/a/{foo}/{foo}")
+	_, err = ValidatePathTemplateBytes(path)
+	assert.Error(t, err, "Variable name is duplicated: foo")
+	copy(path, "/b/{bar}/{bar}")
+	assert.Error(t, err, "Variable name is duplicated: foo")
+
+	_, err = ValidatePathTemplateBytes(nil)
+	assert.Error(t, err, "PathTemplate must start with a /: ")
+
+	// hooks keep their own copy of the template
+	var started string
+	path = []byte("/a/{foo}")
+	_, err = ValidatePathTemplateBytes(path, WithHooks(Hooks{OnParseStart: func(template string) { started = template }}))
+	assert.NilError(t, err)
+	copy(path, "/b/{bar}")
+	assert.Equal(t, started, "/a/{foo}")
+}
@@ -0,0 +1,185 @@
+package path_template
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Kind classifies a path template by how it matches, so exact templates can be served from a hash map
+type Kind int
+
+const (
+	// KindExact templates are made of literals only - /api/v1/users
+	KindExact Kind = iota
+	// KindPrefix templates are literals followed by a final text glob - /static/** or /static/{path=**}
+	KindPrefix
+	// KindSingleWildcard templates have exactly one path glob - /users/{id}/profile
+	KindSingleWildcard
+	// KindMultiWildcard templates have several path globs - /users/{id}/posts/*
+	KindMultiWildcard
+	// KindComplex templates are anything else, ie prefixed or suffixed operators or a text glob followed by literals
+	KindComplex
+)
+
+func (k Kind) String() string {
+	switch k {
+	case KindExact:
+		return "exact"
+	case KindPrefix:
+		return "prefix"
+	case KindSingleWildcard:
+		return "single-wildcard"
+	case KindMultiWildcard:
+		return "multi-wildcard"
+	case KindComplex:
+		return "complex"
+	default:
+		return fmt.Sprintf("Kind(%d)", int(k))
+	}
+}
+
+// tokenKind is the kind of a single path segment matcher
+type tokenKind int
+
+const (
+	tokenLiteral tokenKind = iota
+	// tokenPathGlob matches a single path segment
+	tokenPathGlob
+	// tokenTextGlob matches zero or more path segments
+	tokenTextGlob
+)
+
+// token matches path segments, variables are flattened into the tokens of their pattern
+type token struct {
+	kind    tokenKind
+	literal string
+	// variable capturing the token, empty for bare operators and literals
+	variable string
+	// prefix preceding the token, set on the first token of a prefixed operator
+	prefix string
+	// suffix following the token, set on the last token of a suffixed operator
+	suffix string
+	// optional is set on the first token of the optional segments, the tokens from there on
+	// are matched all together or not at all
+	optional bool
+}
+
+// flattenTokens returns the segment matchers of a valid parsed template along with the suffix
+// of the final one, if any. A trailing slash is an empty literal, optional with the optional segments.
+// Example: /a/{b=c/*}/**.m3u8 -> [a, c, *, **] and .m3u8
+func flattenTokens(parsed *ParsedTemplate) ([]token, string) {
+	tokens := []token{}
+	for i, node := range parsed.Segments {
+		first := len(tokens)
+		switch node.Kind {
+		case NodeLiteral:
+			tokens = append(tokens, token{kind: tokenLiteral, literal: node.Text})
+		case NodePathGlob:
+			tokens = append(tokens, token{kind: tokenPathGlob})
+		case NodeTextGlob:
+			tokens = append(tokens, token{kind: tokenTextGlob})
+		case NodeVariable:
+			// {foo} is the same as {foo=*}
+			pattern := node.Pattern
+			if len(pattern) == 0 {
+				pattern = textGlob
+			}
+			for _, patternSegment := range strings.Split(pattern, "/") {
+				switch patternSegment {
+				case textGlob:
+					tokens = append(tokens, token{kind: tokenPathGlob, variable: node.Name})
+				case pathGlob:
+					tokens = append(tokens, token{kind: tokenTextGlob, variable: node.Name})
+				default:
+					tokens = append(tokens, token{kind: tokenLiteral, literal: patternSegment, variable: node.Name})
+				}
+			}
+		}
+		tokens[first].prefix = node.Prefix
+		tokens[first].optional = i == parsed.Optional && i > 0
+		tokens[len(tokens)-1].suffix = node.Suffix
+	}
+	// / and /a/ end with an empty segment
+	if strings.HasSuffix(strings.TrimSuffix(parsed.Text, "]"), "/") {
+		tokens = append(tokens, token{kind: tokenLiteral})
+	}
+	return tokens, tokens[len(tokens)-1].suffix
+}
+
+// joinTokenTexts returns the path made of the texts matched by tokens, along with their prefixes
+// and suffixes. The suffix of the final token is replaced by suffix.
+func joinTokenTexts(tokens []token, parts []string, suffix string) string {
+	texts := slices.Clone(parts)
+	for i := 0; i < len(texts) && i < len(tokens); i++ {
+		texts[i] = tokens[i].prefix + texts[i]
+		if i < len(texts)-1 && i < len(tokens)-1 {
+			texts[i] += tokens[i].suffix
+		}
+	}
+	return "/" + strings.Join(texts, "/") + suffix
+}
+
+// Classify returns the Kind of a path template
+func Classify(path string, opts ...Option) (Kind, error) {
+	parsed, err := parseValid(path, opts)
+	if err != nil {
+		return KindComplex, err
+	}
+	if hasReusedVariable(parsed) || parsed.Optional > 0 {
+		return KindComplex, nil
+	}
+	tokens, _ := flattenTokens(parsed)
+	return classifyTokens(tokens), nil
+}
+
+func classifyTokens(tokens []token) Kind {
+	if slices.ContainsFunc(tokens, func(t token) bool { return len(t.prefix) > 0 || len(t.suffix) > 0 }) {
+		return KindComplex
+	}
+
+	pathGlobs, textGlobs := 0, 0
+	for _, t := range tokens {
+		switch t.kind {
+		case tokenPathGlob:
+			pathGlobs++
+		case tokenTextGlob:
+			textGlobs++
+		}
+	}
+
+	switch {
+	case pathGlobs == 0 && textGlobs == 0:
+		return KindExact
+	case pathGlobs == 0 && textGlobs == 1 && tokens[len(tokens)-1].kind == tokenTextGlob:
+		return KindPrefix
+	case textGlobs > 0:
+		return KindComplex
+	case pathGlobs == 1:
+		return KindSingleWildcard
+	default:
+		return KindMultiWildcard
+	}
+}
+
+// IsExact reports whether path is a valid path template made of literals only
+func IsExact(path string, opts ...Option) bool {
+	kind, err := Classify(path, opts...)
+	return err == nil && kind == KindExact
+}
+
+// HasCatchAll reports whether path is a valid path template containing a text glob (**),
+// either bare or inside a variable pattern
+func HasCatchAll(path string, opts ...Option) bool {
+	parsed, err := parseValid(path, opts)
+	if err != nil {
+		return false
+	}
+	tokens, _ := flattenTokens(parsed)
+	for _, t := range tokens {
+		if t.kind == tokenTextGlob {
+			return true
+		}
+	}
+	return false
+}
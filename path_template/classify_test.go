@@ -0,0 +1,53 @@
+package path_template
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestClassify(t *testing.T) {
+	tt := []struct {
+		path string
+		kind Kind
+	}{
+		{path: "/", kind: KindExact},
+		{path: "/api/v1/users", kind: KindExact},
+		{path: "/api/{version=v1}/users", kind: KindExact},
+		{path: "/static/**", kind: KindPrefix},
+		{path: "/static/{path=**}", kind: KindPrefix},
+		{path: "/static/{path=css/**}", kind: KindPrefix},
+		{path: "/users/{id}/profile", kind: KindSingleWildcard},
+		{path: "/users/*", kind: KindSingleWildcard},
+		{path: "/users/{id}/posts/*", kind: KindMultiWildcard},
+		{path: "/users/{id=a/*/*}", kind: KindMultiWildcard},
+		{path: "/**/index.html", kind: KindComplex},
+		{path: "/{tenant}/**", kind: KindComplex},
+		{path: "/media/*.m4s", kind: KindComplex},
+		{path: "/{path=**}.m3u8", kind: KindComplex},
+	}
+	for _, tc := range tt {
+		kind, err := Classify(tc.path)
+		assert.NilError(t, err)
+		assert.Equal(t, kind, tc.kind, tc.path)
+	}
+
+	_, err := Classify("/a//b")
+	assert.Error(t, err, "Empty segment not allowed in path template: a//b")
+}
+
+func TestClassifyPredicates(t *testing.T) {
+	assert.Assert(t, IsExact("/api/v1"))
+	assert.Assert(t, !IsExact("/api/*"))
+	assert.Assert(t, !IsExact("/api//v1"))
+
+	assert.Assert(t, HasCatchAll("/static/**"))
+	assert.Assert(t, HasCatchAll("/media/{path=a/**}.m3u8"))
+	assert.Assert(t, !HasCatchAll("/media/{path=a/*}"))
+	assert.Assert(t, !HasCatchAll("/media/**/**"))
+}
+
+func TestKindString(t *testing.T) {
+	assert.Equal(t, KindPrefix.String(), "prefix")
+	assert.Equal(t, Kind(42).String(), "Kind(42)")
+}
@@ -0,0 +1,20 @@
+// Package v1 freezes the original API of path_template: function signatures without options
+// and the error strings callers may be matching on. Existing callers can keep using it
+// while new code moves to the richer path_template API.
+package v1
+
+import (
+	"github.com/bogdan-deac/path-template/path_template"
+)
+
+// ValidatePathTemplate validates a path template with the default envoy limits
+// and returns the names of its variables
+func ValidatePathTemplate(path string) ([]string, error) {
+	return path_template.ValidatePathTemplate(path)
+}
+
+// ValidatePathTemplateRewrite validates a path template rewrite against the variables
+// of the path template it rewrites
+func ValidatePathTemplateRewrite(pathTemplateRewrite string, variableNames []string) error {
+	return path_template.ValidatePathTemplateRewrite(pathTemplateRewrite, variableNames)
+}
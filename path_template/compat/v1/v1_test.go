@@ -0,0 +1,46 @@
+package v1
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestValidatePathTemplate(t *testing.T) {
+	variables, err := ValidatePathTemplate("/api/{version}/{path=**}.m3u8")
+	assert.NilError(t, err)
+	assert.DeepEqual(t, variables, []string{"version", "path"})
+}
+
+// the error strings of v1 must never change
+func TestValidatePathTemplateErrors(t *testing.T) {
+	tt := []struct {
+		path string
+		err  string
+	}{
+		{path: "api", err: "PathTemplate must start with a /: api"},
+		{path: "/a b", err: "PathTemplate contains non-representable characters: /a b"},
+		{path: "/{a}/{a}", err: "Variable name is duplicated: a"},
+		{path: "/**/*", err: "Cannot have path glob (*) after text glob (**)"},
+		{path: "/**/**", err: "Cannot have text glob (**) after text glob (**)"},
+		{path: "/**/{a}", err: "Cannot have variable after text glob (**): {a}"},
+		{path: "/a*", err: "Prefixes not allowed before operators: a*"},
+		{path: "/{a}/{b}/{c}/{d}/{e}/{f}", err: "Cannot have more than 5 variables: /{a}/{b}/{c}/{d}/{e}/{f}"},
+		{path: "/{abcdefghijklmnopq}", err: "Variable name exceeds 16 characters: abcdefghijklmnopq"},
+		{path: "/{a=}", err: "Variable pattern is empty for: a"},
+		{path: "/{a=b*}", err: "Prefixes or suffixes not allowed with variable pattern operators: b*"},
+		{path: "/*.ts/a", err: "The suffixed operator must in be the final path component: /*.ts/a"},
+	}
+	for _, tc := range tt {
+		_, err := ValidatePathTemplate(tc.path)
+		assert.Error(t, err, tc.err)
+	}
+}
+
+func TestValidatePathTemplateRewrite(t *testing.T) {
+	assert.NilError(t, ValidatePathTemplateRewrite("/{path}/index.html", []string{"path"}))
+	assert.Error(t, ValidatePathTemplateRewrite("/{other}", []string{"path"}),
+		"Variable other in path template rewrite is not present in the path template: /{other}")
+	assert.Error(t, ValidatePathTemplateRewrite("path", []string{"path"}),
+		"Replace path template must start with a /: path")
+}
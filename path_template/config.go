@@ -0,0 +1,105 @@
+package path_template
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// Dialect is a named set of path template rules, see Dialects
+type Dialect string
+
+// DialectEnvoy follows envoy's uri_template extension, the default
+const DialectEnvoy Dialect = "envoy"
+
+// Options declares validation options in config, so validation can be tuned without recompiling:
+//
+//	{"max_variables": 8, "strict_rfc3986": true}
+//
+// Zero fields keep the defaults. Unknown fields are rejected when unmarshaling.
+type Options struct {
+	// Dialect is the path template dialect, see Dialects
+	Dialect Dialect `json:"dialect,omitempty"`
+	// MaxVariables is the maximum number of variables in a path template
+	MaxVariables int `json:"max_variables,omitempty"`
+	// MinVariableNameLength is the minimum length of a variable name
+	MinVariableNameLength int `json:"min_variable_name_length,omitempty"`
+	// MaxVariableNameLength is the maximum length of a variable name
+	MaxVariableNameLength int `json:"max_variable_name_length,omitempty"`
+	// StrictRFC3986 is WithStrictRFC3986
+	StrictRFC3986 bool `json:"strict_rfc3986,omitempty"`
+	// SuffixVariable is WithSuffixVariable
+	SuffixVariable bool `json:"suffix_variable,omitempty"`
+	// CollectAllErrors is WithCollectAllErrors
+	CollectAllErrors bool `json:"collect_all_errors,omitempty"`
+	// MaxDiagnostics is WithMaxDiagnostics
+	MaxDiagnostics int `json:"max_diagnostics,omitempty"`
+}
+
+// UnmarshalJSON decodes options strictly, rejecting unknown fields and invalid values
+func (o *Options) UnmarshalJSON(data []byte) error {
+	// the alias has no methods, so decoding it doesn't recurse
+	type plain Options
+	var decoded plain
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&decoded); err != nil {
+		return fmt.Errorf("Invalid options: %w", err)
+	}
+	if err := Options(decoded).Validate(); err != nil {
+		return err
+	}
+	*o = Options(decoded)
+	return nil
+}
+
+// Validate checks the values of the options
+func (o Options) Validate() error {
+	if len(o.Dialect) > 0 && !isDialect(o.Dialect) {
+		return fmt.Errorf("Unsupported dialect: %s", excerpt(string(o.Dialect)))
+	}
+	for _, option := range []struct {
+		name  string
+		value int
+	}{
+		{name: "max_variables", value: o.MaxVariables},
+		{name: "min_variable_name_length", value: o.MinVariableNameLength},
+		{name: "max_variable_name_length", value: o.MaxVariableNameLength},
+		{name: "max_diagnostics", value: o.MaxDiagnostics},
+	} {
+		if option.value < 0 {
+			return fmt.Errorf("Option cannot be negative: %s", option.name)
+		}
+	}
+	limits := newOptions(o.Options()).limits
+	if limits.MinVariableNameLength > limits.MaxVariableNameLength {
+		return fmt.Errorf("Option min_variable_name_length exceeds max_variable_name_length: %d", limits.MinVariableNameLength)
+	}
+	return nil
+}
+
+// Options returns the functional options declared
+func (o Options) Options() []Option {
+	opts := []Option{}
+	if len(o.Dialect) > 0 {
+		opts = append(opts, WithDialect(o.Dialect))
+	}
+	opts = append(opts, WithLimits(ValidationLimits{
+		MaxVariables:          o.MaxVariables,
+		MinVariableNameLength: o.MinVariableNameLength,
+		MaxVariableNameLength: o.MaxVariableNameLength,
+	}))
+	if o.StrictRFC3986 {
+		opts = append(opts, WithStrictRFC3986())
+	}
+	if o.SuffixVariable {
+		opts = append(opts, WithSuffixVariable())
+	}
+	if o.CollectAllErrors {
+		opts = append(opts, WithCollectAllErrors())
+	}
+	if o.MaxDiagnostics > 0 {
+		opts = append(opts, WithMaxDiagnostics(o.MaxDiagnostics))
+	}
+	return opts
+}
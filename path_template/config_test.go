@@ -0,0 +1,46 @@
+package path_template
+
+import (
+	"encoding/json"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestOptionsUnmarshal(t *testing.T) {
+	var o Options
+	assert.NilError(t, json.Unmarshal([]byte(`{"dialect": "envoy", "max_variables": 8, "strict_rfc3986": true}`), &o))
+	assert.DeepEqual(t, o, Options{Dialect: DialectEnvoy, MaxVariables: 8, StrictRFC3986: true})
+
+	assert.DeepEqual(t, Limits(o.Options()...), ValidationLimits{
+		MaxVariables:          8,
+		MinVariableNameLength: 1,
+		MaxVariableNameLength: 16,
+	})
+	_, err := ValidatePathTemplate("/{a}/{b}/{c}/{d}/{e}/{f}", o.Options()...)
+	assert.NilError(t, err)
+	_, err = ValidatePathTemplate("/a=b", o.Options()...)
+	assert.ErrorContains(t, err, "strict RFC 3986")
+}
+
+func TestOptionsUnmarshalFailure(t *testing.T) {
+	tt := []struct {
+		json string
+		err  string
+	}{
+		{json: `{"allow_utf8": true}`, err: `Invalid options: json: unknown field "allow_utf8"`},
+		{json: `{"max_variables": "8"}`, err: "Invalid options: json: cannot unmarshal string"},
+		{json: `{"dialect": "custom"}`, err: "Unsupported dialect: custom"},
+		{json: `{"max_diagnostics": -1}`, err: "Option cannot be negative: max_diagnostics"},
+		{json: `{"min_variable_name_length": 20}`, err: "Option min_variable_name_length exceeds max_variable_name_length: 20"},
+	}
+	for _, tc := range tt {
+		var o Options
+		assert.ErrorContains(t, json.Unmarshal([]byte(tc.json), &o), tc.err, tc.json)
+	}
+}
+
+func TestOptionsDefaults(t *testing.T) {
+	assert.NilError(t, Options{}.Validate())
+	assert.DeepEqual(t, Limits(Options{}.Options()...), Limits())
+}
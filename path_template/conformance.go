@@ -0,0 +1,78 @@
+package path_template
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ConformanceRequest is a request a gateway routes with a path template, along with the path
+// the backend receives once the path template rewrite is applied
+type ConformanceRequest struct {
+	// Name describes the sample path, see TestCase
+	Name        string
+	Method      string
+	Path        string
+	BackendPath string
+}
+
+// GenerateConformanceRequests returns sample requests matched by a route along with the paths its
+// backend receives, so backend teams can verify they handle what the gateway actually sends.
+// The rewrite is optional, without it the backend receives the request path.
+func GenerateConformanceRequests(method, match, rewrite string) ([]ConformanceRequest, error) {
+	variableNames, err := ValidatePathTemplate(match)
+	if err != nil {
+		return nil, err
+	}
+	if len(rewrite) > 0 {
+		if err := ValidatePathTemplateRewrite(rewrite, variableNames); err != nil {
+			return nil, err
+		}
+	}
+
+	cases, err := GenerateTestCases(match)
+	if err != nil {
+		return nil, err
+	}
+	requests := []ConformanceRequest{}
+	for _, tc := range cases {
+		if !tc.Match {
+			continue
+		}
+		backendPath := tc.Path
+		if len(rewrite) > 0 {
+			backendPath = expandRewrite(rewrite, tc.Variables)
+		}
+		requests = append(requests, ConformanceRequest{Name: tc.Name, Method: method, Path: tc.Path, BackendPath: backendPath})
+	}
+	return requests, nil
+}
+
+// expandRewrite replaces the variables of a valid path template rewrite with their values
+func expandRewrite(rewrite string, bindings map[string]string) string {
+	var sb strings.Builder
+	rest := rewrite
+	for {
+		start := strings.IndexRune(rest, '{')
+		if start < 0 {
+			sb.WriteString(rest)
+			return sb.String()
+		}
+		end := start + strings.IndexRune(rest[start:], '}')
+		sb.WriteString(rest[:start])
+		sb.WriteString(bindings[rest[start+1:end]])
+		rest = rest[end+1:]
+	}
+}
+
+// WriteHTTPFile writes requests as an HTTP file, as run by the REST clients of common editors,
+// sending them to baseURL. The expected backend path is recorded as a comment of each request.
+func WriteHTTPFile(w io.Writer, baseURL string, requests []ConformanceRequest) error {
+	baseURL = strings.TrimSuffix(baseURL, "/")
+	for _, r := range requests {
+		if _, err := fmt.Fprintf(w, "### %s\n# backend path: %s\n%s %s%s\n\n", r.Name, r.BackendPath, r.Method, baseURL, r.Path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
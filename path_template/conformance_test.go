@@ -0,0 +1,38 @@
+package path_template
+
+import (
+	"strings"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestGenerateConformanceRequests(t *testing.T) {
+	requests, err := GenerateConformanceRequests("GET", "/media/{id}/{path=**}.m3u8", "/v2/{id}/{path}/index.m3u8")
+	assert.NilError(t, err)
+	assert.DeepEqual(t, requests, []ConformanceRequest{
+		{Name: "typical", Method: "GET", Path: "/media/v1/v2.m3u8", BackendPath: "/v2/v1/v2/index.m3u8"},
+		{Name: "empty text glob", Method: "GET", Path: "/media/v1/.m3u8", BackendPath: "/v2/v1//index.m3u8"},
+		{Name: "deep text glob", Method: "GET", Path: "/media/v1/v3/v4/v5.m3u8", BackendPath: "/v2/v1/v3/v4/v5/index.m3u8"},
+		{Name: "value ending with the suffix", Method: "GET", Path: "/media/v1/v2.m3u8.m3u8", BackendPath: "/v2/v1/v2.m3u8/index.m3u8"},
+	})
+
+	requests, err = GenerateConformanceRequests("POST", "/api/{id}", "")
+	assert.NilError(t, err)
+	assert.DeepEqual(t, requests, []ConformanceRequest{
+		{Name: "typical", Method: "POST", Path: "/api/v1", BackendPath: "/api/v1"},
+	})
+
+	_, err = GenerateConformanceRequests("GET", "/api/{id}", "/{other}")
+	assert.Error(t, err, "Variable other in path template rewrite is not present in the path template: /{other}")
+}
+
+func TestWriteHTTPFile(t *testing.T) {
+	var sb strings.Builder
+	assert.NilError(t, WriteHTTPFile(&sb, "https://gateway.local/", []ConformanceRequest{
+		{Name: "typical", Method: "GET", Path: "/api/v1", BackendPath: "/users/v1"},
+		{Name: "empty text glob", Method: "GET", Path: "/static/", BackendPath: "/"},
+	}))
+	assert.Equal(t, sb.String(), "### typical\n# backend path: /users/v1\nGET https://gateway.local/api/v1\n\n"+
+		"### empty text glob\n# backend path: /\nGET https://gateway.local/static/\n\n")
+}
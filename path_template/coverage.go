@@ -0,0 +1,331 @@
+package path_template
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// Coverage, overlap and equivalence are decided exactly on the languages of the regular
+// expressions envoy builds from templates: a template matches / followed by its segments
+// joined by slashes, where literals match themselves, path globs (*) one or more pchars and
+// text globs (**) any sequence of pchars and slashes, followed by the suffix, if any.
+// So a trailing slash is significant, /a/** matches /a/ but not /a.
+//
+// Each template is turned into a nondeterministic automaton over the bytes of a path.
+// Two automata are explored together, determinizing both on the fly (a product of their
+// subset constructions), looking for a path accepted by one and rejected by the other.
+// The search is complete: when no such path exists, none is reported.
+
+// byteClass is the set of bytes accepted by an automaton transition
+type byteClass int
+
+const (
+	// classByte accepts a single byte
+	classByte byteClass = iota
+	// classPchar accepts any pchar, what a path glob is made of
+	classPchar
+	// classAny accepts any pchar or a slash, what a text glob is made of
+	classAny
+	// classNone accepts nothing
+	classNone
+)
+
+// isPchar holds the bytes a path segment may contain
+var isPchar = func() [256]bool {
+	var table [256]bool
+	for c := range 256 {
+		table[c] = validLiteralRe.MatchString(string(rune(c)))
+	}
+	return table
+}()
+
+// transition of an automaton state
+type transition struct {
+	class byteClass
+	char  byte
+}
+
+func (t transition) accepts(c byte) bool {
+	switch t.class {
+	case classByte:
+		return t.char == c
+	case classPchar:
+		return isPchar[c]
+	case classAny:
+		return isPchar[c] || c == '/'
+	default:
+		return false
+	}
+}
+
+// nfaState is a state of a template automaton, which is a chain of states with optional self loops
+type nfaState struct {
+	// loop is the self loop of the state
+	loop transition
+	// next leads to the following state, the last state is accepting and has none
+	next transition
+}
+
+type templateNFA struct {
+	states []nfaState
+	// optional is the state starting the optional segments, with an epsilon transition
+	// to the accepting state skipping them, 0 without optional segments
+	optional int
+}
+
+// newTemplateNFA builds the automaton of a template from its segment matchers
+func newTemplateNFA(tokens []token) *templateNFA {
+	n := &templateNFA{states: []nfaState{{loop: transition{class: classNone}}}}
+	for _, t := range tokens {
+		if t.optional {
+			n.optional = len(n.states) - 1
+		}
+		n.add(transition{class: classByte, char: '/'})
+		for i := 0; i < len(t.prefix); i++ {
+			n.add(transition{class: classByte, char: t.prefix[i]})
+		}
+		switch t.kind {
+		case tokenLiteral:
+			for i := 0; i < len(t.literal); i++ {
+				n.add(transition{class: classByte, char: t.literal[i]})
+			}
+		case tokenPathGlob:
+			// one or more pchars
+			n.add(transition{class: classPchar})
+			n.states[len(n.states)-1].loop = transition{class: classPchar}
+		case tokenTextGlob:
+			// zero or more pchars or slashes
+			n.states[len(n.states)-1].loop = transition{class: classAny}
+		}
+		for i := 0; i < len(t.suffix); i++ {
+			n.add(transition{class: classByte, char: t.suffix[i]})
+		}
+	}
+	return n
+}
+
+// add appends a state reached from the last state through t
+func (n *templateNFA) add(t transition) {
+	n.states[len(n.states)-1].next = t
+	n.states = append(n.states, nfaState{loop: transition{class: classNone}})
+}
+
+// literalBytes returns the bytes of the single byte transitions
+func (n *templateNFA) literalBytes() []byte {
+	chars := []byte{}
+	for _, s := range n.states[:len(n.states)-1] {
+		if s.next.class == classByte {
+			chars = append(chars, s.next.char)
+		}
+	}
+	return chars
+}
+
+// stateSet is a set of automaton states
+type stateSet []uint64
+
+func (n *templateNFA) start() stateSet {
+	set := make(stateSet, (len(n.states)+63)/64)
+	set[0] = 1
+	n.close(set)
+	return set
+}
+
+// close adds to set the state reached by skipping the optional segments, if any
+func (n *templateNFA) close(set stateSet) {
+	if n.optional > 0 && set.has(n.optional) {
+		set.add(len(n.states) - 1)
+	}
+}
+
+func (s stateSet) has(i int) bool {
+	return s[i/64]&(1<<(i%64)) != 0
+}
+
+func (s stateSet) add(i int) {
+	s[i/64] |= 1 << (i % 64)
+}
+
+func (s stateSet) empty() bool {
+	for _, word := range s {
+		if word != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func (s stateSet) appendKey(key []byte) []byte {
+	for _, word := range s {
+		key = binary.LittleEndian.AppendUint64(key, word)
+	}
+	return key
+}
+
+// step returns the states reached from set by reading c
+func (n *templateNFA) step(set stateSet, c byte) stateSet {
+	next := make(stateSet, len(set))
+	for i, s := range n.states {
+		if !set.has(i) {
+			continue
+		}
+		if s.loop.accepts(c) {
+			next.add(i)
+		}
+		if i < len(n.states)-1 && s.next.accepts(c) {
+			next.add(i + 1)
+		}
+	}
+	n.close(next)
+	return next
+}
+
+func (n *templateNFA) accepting(set stateSet) bool {
+	return set.has(len(n.states) - 1)
+}
+
+// matches reports whether the automaton accepts path
+func (n *templateNFA) matches(path string) bool {
+	set := n.start()
+	for i := 0; i < len(path) && !set.empty(); i++ {
+		set = n.step(set, path[i])
+	}
+	return n.accepting(set)
+}
+
+// findPath searches the shortest path for which found returns true, given whether a and b accept it.
+// Paths where both automata are stuck are not explored further when prune returns true for them.
+func findPath(a, b *templateNFA, found func(inA, inB bool) bool, prune func(deadA, deadB bool) bool) (string, bool) {
+	// bytes behaving the same in both automata are explored once: the bytes of the literals,
+	// the slash and a single pchar standing for all the others
+	alphabet := append(a.literalBytes(), b.literalBytes()...)
+	alphabet = append(alphabet, '/')
+	seen := [256]bool{}
+	for _, c := range alphabet {
+		seen[c] = true
+	}
+	for c := range 256 {
+		if isPchar[c] && !seen[c] {
+			alphabet = append(alphabet, byte(c))
+			seen[c] = true
+			break
+		}
+	}
+	unique := alphabet[:0]
+	for _, c := range alphabet {
+		if seen[c] {
+			unique = append(unique, c)
+			seen[c] = false
+		}
+	}
+
+	type node struct {
+		a, b stateSet
+		path string
+	}
+	queue := []node{{a: a.start(), b: b.start()}}
+	visited := map[string]bool{}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		key := string(current.b.appendKey(current.a.appendKey(nil)))
+		if visited[key] {
+			continue
+		}
+		visited[key] = true
+
+		if found(a.accepting(current.a), b.accepting(current.b)) {
+			return current.path, true
+		}
+		if prune(current.a.empty(), current.b.empty()) {
+			continue
+		}
+		for _, c := range unique {
+			queue = append(queue, node{
+				a:    a.step(current.a, c),
+				b:    b.step(current.b, c),
+				path: current.path + string(c),
+			})
+		}
+	}
+	return "", false
+}
+
+// templateAutomaton builds the automaton of a valid parsed template
+func templateAutomaton(p *ParsedTemplate) *templateNFA {
+	tokens, _ := flattenTokens(p)
+	return newTemplateNFA(tokens)
+}
+
+// coversTemplate reports whether a matches every path b matches
+func coversTemplate(a, b *ParsedTemplate) bool {
+	_, found := findPath(templateAutomaton(a), templateAutomaton(b),
+		func(inA, inB bool) bool { return inB && !inA },
+		func(deadA, deadB bool) bool { return deadB },
+	)
+	return !found
+}
+
+// overlapsTemplate reports whether a and b match a common path
+func overlapsTemplate(a, b *ParsedTemplate) bool {
+	_, found := findPath(templateAutomaton(a), templateAutomaton(b),
+		func(inA, inB bool) bool { return inA && inB },
+		func(deadA, deadB bool) bool { return deadA || deadB },
+	)
+	return found
+}
+
+// Covers reports whether path template a matches every path path template b matches.
+// Both are validated with opts, templates with reused variables are not supported.
+func Covers(a, b string, opts ...Option) (bool, error) {
+	parsedA, parsedB, err := parseValidPair(a, b, opts)
+	if err != nil {
+		return false, err
+	}
+	return coversTemplate(parsedA, parsedB), nil
+}
+
+// Overlaps reports whether path templates a and b, validated with opts, match at least one common path
+func Overlaps(a, b string, opts ...Option) (bool, error) {
+	parsedA, parsedB, err := parseValidPair(a, b, opts)
+	if err != nil {
+		return false, err
+	}
+	return overlapsTemplate(parsedA, parsedB), nil
+}
+
+// Equivalent reports whether path templates a and b match exactly the same paths,
+// regardless of variable names - /{id} and /* are equivalent. Both are validated with opts.
+func Equivalent(a, b string, opts ...Option) (bool, error) {
+	parsedA, parsedB, err := parseValidPair(a, b, opts)
+	if err != nil {
+		return false, err
+	}
+	return coversTemplate(parsedA, parsedB) && coversTemplate(parsedB, parsedA), nil
+}
+
+func parseValidPair(a, b string, opts []Option) (*ParsedTemplate, *ParsedTemplate, error) {
+	parsedA, err := parseAutomatonTemplate(a, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+	parsedB, err := parseAutomatonTemplate(b, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+	return parsedA, parsedB, nil
+}
+
+// parseAutomatonTemplate parses a valid template whose paths an automaton can match: a reused
+// variable binds the same value twice, which takes more than a regular language
+func parseAutomatonTemplate(template string, opts []Option) (*ParsedTemplate, error) {
+	parsed, err := parseValid(template, opts)
+	if err != nil {
+		return nil, err
+	}
+	if hasReusedVariable(parsed) {
+		return nil, fmt.Errorf("Reused variables are not supported by template relations: %s", excerpt(template))
+	}
+	return parsed, nil
+}
@@ -0,0 +1,88 @@
+package path_template
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestCoversAndOverlaps(t *testing.T) {
+	tt := []struct {
+		a, b     string
+		covers   bool
+		overlaps bool
+	}{
+		{a: "/a", b: "/a", covers: true, overlaps: true},
+		{a: "/*", b: "/a", covers: true, overlaps: true},
+		{a: "/a", b: "/*", covers: false, overlaps: true},
+		{a: "/**", b: "/", covers: true, overlaps: true},
+		{a: "/a/**", b: "/a/*/b/**", covers: true, overlaps: true},
+		{a: "/a/*/b/**", b: "/a/**", covers: false, overlaps: true},
+		{a: "/a/*", b: "/b/*", covers: false, overlaps: false},
+		{a: "/**/a", b: "/b/**", covers: false, overlaps: true},
+		{a: "/*/*", b: "/*", covers: false, overlaps: false},
+		{a: "/**.ts", b: "/a/*.ts", covers: true, overlaps: true},
+		{a: "/**.ts", b: "/a/*-v1.ts", covers: true, overlaps: true},
+		{a: "/*.ts", b: "/*.m3u8", covers: false, overlaps: false},
+		{a: "/*.ts", b: "/*-v1.ts", covers: true, overlaps: true},
+		{a: "/**.ts", b: "/**s", covers: false, overlaps: true},
+		{a: "/**", b: "/**.ts", covers: true, overlaps: true},
+		{a: "/a/*", b: "/*/b", covers: false, overlaps: true},
+		{a: "/{x=a/*}", b: "/a/{y}", covers: true, overlaps: true},
+		{a: "/a/**", b: "/a", covers: false, overlaps: false},
+		{a: "/a/**", b: "/a/", covers: true, overlaps: true},
+		{a: "/a/*", b: "/a/", covers: false, overlaps: false},
+		{a: "/a/**", b: "/a/**/b", covers: true, overlaps: true},
+		{a: "/a/*/**", b: "/a/**", covers: false, overlaps: true},
+	}
+	for _, tc := range tt {
+		a, err := parseValid(tc.a, nil)
+		assert.NilError(t, err)
+		b, err := parseValid(tc.b, nil)
+		assert.NilError(t, err)
+		assert.Equal(t, coversTemplate(a, b), tc.covers, "%s covers %s", tc.a, tc.b)
+		assert.Equal(t, overlapsTemplate(a, b), tc.overlaps, "%s overlaps %s", tc.a, tc.b)
+		assert.Equal(t, overlapsTemplate(b, a), tc.overlaps, "%s overlaps %s", tc.b, tc.a)
+	}
+}
+
+func TestCoversOverlapsEquivalent(t *testing.T) {
+	tt := []struct {
+		a, b       string
+		opts       []Option
+		covers     bool
+		overlaps   bool
+		equivalent bool
+	}{
+		{a: "/{id}", b: "/*", covers: true, overlaps: true, equivalent: true},
+		{a: "/{path=**}", b: "/**", covers: true, overlaps: true, equivalent: true},
+		{a: "/{x=a/*}/b", b: "/a/{y}/b", covers: true, overlaps: true, equivalent: true},
+		{a: "/**", b: "/a/**", covers: true, overlaps: true, equivalent: false},
+		{a: "/a/**", b: "/**", covers: false, overlaps: true, equivalent: false},
+		{a: "/a", b: "/a/", covers: false, overlaps: false, equivalent: false},
+		{a: "/{a}-v1/{b}.ts", b: "/*-v1/*.ts", opts: []Option{WithMultipleSuffixes()}, covers: true, overlaps: true, equivalent: true},
+		{a: "/*-v1/*", b: "/x-v1/y.ts", opts: []Option{WithMultipleSuffixes()}, covers: true, overlaps: true, equivalent: false},
+		{a: "/*-v1/*.ts", b: "/*-v2/*.ts", opts: []Option{WithMultipleSuffixes()}, covers: false, overlaps: false, equivalent: false},
+		{a: "/v{major}", b: "/v*", opts: []Option{WithOperatorPrefixes()}, covers: true, overlaps: true, equivalent: true},
+		{a: "/v{major}/x", b: "/{a}/x", opts: []Option{WithOperatorPrefixes()}, covers: false, overlaps: true, equivalent: false},
+		{a: "/{a}/x", b: "/v{major}/x", opts: []Option{WithOperatorPrefixes()}, covers: true, overlaps: true, equivalent: false},
+	}
+	for _, tc := range tt {
+		covers, err := Covers(tc.a, tc.b, tc.opts...)
+		assert.NilError(t, err)
+		assert.Equal(t, covers, tc.covers, "%s covers %s", tc.a, tc.b)
+		overlaps, err := Overlaps(tc.a, tc.b, tc.opts...)
+		assert.NilError(t, err)
+		assert.Equal(t, overlaps, tc.overlaps, "%s overlaps %s", tc.a, tc.b)
+		equivalent, err := Equivalent(tc.a, tc.b, tc.opts...)
+		assert.NilError(t, err)
+		assert.Equal(t, equivalent, tc.equivalent, "%s equivalent to %s", tc.a, tc.b)
+	}
+
+	_, err := Covers("/a", "invalid")
+	assert.ErrorContains(t, err, "")
+	_, err = Equivalent("/{x}/{x}", "/a")
+	assert.ErrorContains(t, err, "")
+	_, err = Covers("/{id}/{id}", "/*/*", WithVariableReuse())
+	assert.Error(t, err, "Reused variables are not supported by template relations: /{id}/{id}")
+}
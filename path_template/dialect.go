@@ -0,0 +1,43 @@
+package path_template
+
+import "slices"
+
+const (
+	// DialectStrict is DialectEnvoy with literals validated strictly per RFC 3986, see WithStrictRFC3986
+	DialectStrict Dialect = "strict"
+	// DialectExtended is DialectEnvoy with the extensions for in-process routers, see WithMultipleSuffixes,
+	// WithOperatorPrefixes, WithVariableReuse and WithOptionalSegments
+	DialectExtended Dialect = "extended"
+)
+
+// Dialects returns the supported dialects
+func Dialects() []Dialect {
+	return []Dialect{DialectEnvoy, DialectStrict, DialectExtended}
+}
+
+// WithDialect validates path templates following the rules of dialect.
+// Options applied after it refine the dialect, ie limits.
+func WithDialect(dialect Dialect) Option {
+	return func(o *options) {
+		o.strictRFC3986 = dialect == DialectStrict
+		o.multipleSuffixes = dialect == DialectExtended
+		o.operatorPrefixes = dialect == DialectExtended
+		o.variableReuse = dialect == DialectExtended
+		o.optionalSegments = dialect == DialectExtended
+	}
+}
+
+// CompareDialects validates a path template in every dialect and returns the validation error
+// of each, nil when the dialect accepts it, so migrations between dialects can be planned
+func CompareDialects(template string) map[Dialect]error {
+	results := map[Dialect]error{}
+	for _, dialect := range Dialects() {
+		_, results[dialect] = ValidatePathTemplate(template, WithDialect(dialect))
+	}
+	return results
+}
+
+// isDialect reports whether dialect is supported
+func isDialect(dialect Dialect) bool {
+	return slices.Contains(Dialects(), dialect)
+}
@@ -0,0 +1,38 @@
+package path_template
+
+import (
+	"encoding/json"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestCompareDialects(t *testing.T) {
+	results := CompareDialects("/api/{id}")
+	assert.Equal(t, len(results), len(Dialects()))
+	for dialect, err := range results {
+		assert.NilError(t, err, dialect)
+	}
+
+	results = CompareDialects("/a=b/%2f")
+	assert.NilError(t, results[DialectEnvoy])
+	assert.Error(t, results[DialectStrict], "Bare = not allowed in literal in strict RFC 3986 mode: a=b")
+
+	results = CompareDialects("/{a}/{a}")
+	assert.Error(t, results[DialectEnvoy], "Variable name is duplicated: a")
+	assert.Error(t, results[DialectStrict], "Variable name is duplicated: a")
+	assert.NilError(t, results[DialectExtended])
+}
+
+func TestWithDialect(t *testing.T) {
+	_, err := ValidatePathTemplate("/a=b", WithDialect(DialectStrict))
+	assert.ErrorContains(t, err, "strict RFC 3986")
+	// the last dialect wins
+	_, err = ValidatePathTemplate("/a=b", WithDialect(DialectStrict), WithDialect(DialectEnvoy))
+	assert.NilError(t, err)
+
+	var o Options
+	assert.NilError(t, json.Unmarshal([]byte(`{"dialect": "strict"}`), &o))
+	_, err = ValidatePathTemplate("/a=b", o.Options()...)
+	assert.ErrorContains(t, err, "strict RFC 3986")
+}
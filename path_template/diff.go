@@ -0,0 +1,153 @@
+package path_template
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ChangeKind is the kind of a SegmentChange
+type ChangeKind int
+
+const (
+	// ChangeAdded is a segment only found in the new template
+	ChangeAdded ChangeKind = iota
+	// ChangeRemoved is a segment only found in the old template
+	ChangeRemoved
+	// ChangeModified is a segment replaced by another one
+	ChangeModified
+)
+
+func (k ChangeKind) String() string {
+	switch k {
+	case ChangeAdded:
+		return "added"
+	case ChangeRemoved:
+		return "removed"
+	case ChangeModified:
+		return "modified"
+	default:
+		return fmt.Sprintf("ChangeKind(%d)", int(k))
+	}
+}
+
+// SegmentChange is a difference between the segments of two path templates.
+// A trailing slash is an empty literal segment.
+type SegmentChange struct {
+	Kind ChangeKind
+	// Before is the segment of the old template, zero for ChangeAdded
+	Before Node
+	// After is the segment of the new template, zero for ChangeRemoved
+	After Node
+	// Detail describes a ChangeModified - literal -> variable, pattern changed
+	Detail string
+}
+
+func (c SegmentChange) String() string {
+	switch c.Kind {
+	case ChangeAdded:
+		return fmt.Sprintf("+ %s", segmentLabel(c.After))
+	case ChangeRemoved:
+		return fmt.Sprintf("- %s", segmentLabel(c.Before))
+	default:
+		return fmt.Sprintf("~ %s -> %s (%s)", segmentLabel(c.Before), segmentLabel(c.After), c.Detail)
+	}
+}
+
+func segmentLabel(n Node) string {
+	if len(n.Text) == 0 {
+		return "trailing slash"
+	}
+	return n.Text
+}
+
+// DiffTemplates returns the segments added, removed and modified from path template a to b,
+// in order, for human readable summaries of route changes. Unchanged segments are not reported.
+// Removed and added segments found between the same unchanged segments are paired as modified.
+func DiffTemplates(a, b string) ([]SegmentChange, error) {
+	parsedA, parsedB, err := parseValidPair(a, b, nil)
+	if err != nil {
+		return nil, err
+	}
+	before, after := diffSegments(parsedA), diffSegments(parsedB)
+
+	// longest common subsequence of the segments
+	lcs := make([][]int, len(before)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(after)+1)
+	}
+	for i := len(before) - 1; i >= 0; i-- {
+		for j := len(after) - 1; j >= 0; j-- {
+			if before[i].Text == after[j].Text {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	changes := []SegmentChange{}
+	removed, added := []Node{}, []Node{}
+	// flush pairs the removed and added segments of a run of changes
+	flush := func() {
+		for k := 0; k < len(removed) || k < len(added); k++ {
+			switch {
+			case k >= len(added):
+				changes = append(changes, SegmentChange{Kind: ChangeRemoved, Before: removed[k]})
+			case k >= len(removed):
+				changes = append(changes, SegmentChange{Kind: ChangeAdded, After: added[k]})
+			default:
+				changes = append(changes, SegmentChange{
+					Kind:   ChangeModified,
+					Before: removed[k],
+					After:  added[k],
+					Detail: describeSegmentChange(removed[k], added[k]),
+				})
+			}
+		}
+		removed, added = removed[:0], added[:0]
+	}
+	i, j := 0, 0
+	for i < len(before) || j < len(after) {
+		switch {
+		case i < len(before) && j < len(after) && before[i].Text == after[j].Text:
+			flush()
+			i++
+			j++
+		case j == len(after) || (i < len(before) && lcs[i+1][j] >= lcs[i][j+1]):
+			removed = append(removed, before[i])
+			i++
+		default:
+			added = append(added, after[j])
+			j++
+		}
+	}
+	flush()
+	return changes, nil
+}
+
+// diffSegments returns the segments of a valid parsed template, a trailing slash is an empty literal
+func diffSegments(parsed *ParsedTemplate) []Node {
+	segments := parsed.Segments
+	if len(segments) > 0 && strings.HasSuffix(parsed.Text, "/") {
+		segments = append(segments[:len(segments):len(segments)], Node{Kind: NodeLiteral, Range: Range{Start: len(parsed.Text), End: len(parsed.Text)}})
+	}
+	return segments
+}
+
+// describeSegmentChange describes how segment a was changed into b
+func describeSegmentChange(a, b Node) string {
+	switch {
+	case a.Kind != b.Kind:
+		return fmt.Sprintf("%s -> %s", a.Kind, b.Kind)
+	case a.Kind == NodeVariable && a.Name != b.Name && a.Pattern == b.Pattern && a.Suffix == b.Suffix:
+		return "variable renamed"
+	case a.Kind == NodeVariable && a.Pattern != b.Pattern:
+		return "pattern changed"
+	case a.Suffix != b.Suffix:
+		return "suffix changed"
+	case a.Kind == NodeLiteral:
+		return "literal changed"
+	default:
+		return "variable changed"
+	}
+}
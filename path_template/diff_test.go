@@ -0,0 +1,48 @@
+package path_template
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestDiffTemplates(t *testing.T) {
+	tt := []struct {
+		a, b     string
+		expected []string
+	}{
+		{a: "/api/{id}", b: "/api/{id}", expected: []string{}},
+		{a: "/api/users/profile", b: "/api/{user}/profile", expected: []string{"~ users -> {user} (literal -> variable)"}},
+		{a: "/api/{id}", b: "/api/{user_id}", expected: []string{"~ {id} -> {user_id} (variable renamed)"}},
+		{a: "/api/{p=a/*}", b: "/api/{p=b/*}", expected: []string{"~ {p=a/*} -> {p=b/*} (pattern changed)"}},
+		{a: "/media/**.ts", b: "/media/**.m3u8", expected: []string{"~ **.ts -> **.m3u8 (suffix changed)"}},
+		{a: "/v1/users", b: "/v2/users", expected: []string{"~ v1 -> v2 (literal changed)"}},
+		{a: "/api/users", b: "/api/v2/users", expected: []string{"+ v2"}},
+		{a: "/api/v2/users/{id}", b: "/api/users", expected: []string{"- v2", "- {id}"}},
+		{a: "/api", b: "/api/", expected: []string{"+ trailing slash"}},
+		{a: "/a/b/c", b: "/x/b/y/z", expected: []string{"~ a -> x (literal changed)", "~ c -> y (literal changed)", "+ z"}},
+		{a: "/", b: "/a", expected: []string{"+ a"}},
+	}
+	for _, tc := range tt {
+		changes, err := DiffTemplates(tc.a, tc.b)
+		assert.NilError(t, err)
+		summary := []string{}
+		for _, c := range changes {
+			summary = append(summary, c.String())
+		}
+		assert.DeepEqual(t, summary, tc.expected)
+	}
+
+	_, err := DiffTemplates("/a", "/{b")
+	assert.ErrorContains(t, err, "Unmatched {")
+}
+
+func TestDiffTemplatesNodes(t *testing.T) {
+	changes, err := DiffTemplates("/a/{id}", "/a/*")
+	assert.NilError(t, err)
+	assert.Equal(t, len(changes), 1)
+	assert.Equal(t, changes[0].Kind, ChangeModified)
+	assert.Equal(t, changes[0].Before.Name, "id")
+	assert.Equal(t, changes[0].After.Kind, NodePathGlob)
+	assert.Equal(t, changes[0].Detail, "variable -> path-glob")
+}
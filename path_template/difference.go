@@ -0,0 +1,123 @@
+package path_template
+
+import (
+	"slices"
+	"strings"
+)
+
+const (
+	// maxDifferenceCandidates bounds the number of candidate paths tried by MatchDifferenceSample
+	maxDifferenceCandidates = 10000
+	// maxTextGlobSegments is the maximum number of segments a text glob expands to in samples
+	maxTextGlobSegments = 2
+)
+
+// MatchDifferenceSample returns up to n example paths matched by template a but not by template b,
+// making overlap and compatibility reports concrete. The first sample is the shortest such path,
+// the others are generated by expanding the operators of a with a fresh value and the literals of b.
+// So the result is empty exactly when every path matched by a is also matched by b.
+// Both are validated with opts, as by Covers.
+func MatchDifferenceSample(a, b string, n int, opts ...Option) ([]string, error) {
+	parsedA, parsedB, err := parseValidPair(a, b, opts)
+	if err != nil {
+		return nil, err
+	}
+	tokensA, suffixA := flattenTokens(parsedA)
+	tokensB, _ := flattenTokens(parsedB)
+	automatonA := templateAutomaton(parsedA)
+	automatonB := templateAutomaton(parsedB)
+
+	// values used for operators: the literals of b, to probe its boundaries, and a value of neither template
+	values := []string{}
+	for _, t := range slices.Concat(tokensB, tokensA) {
+		if t.kind == tokenLiteral && len(t.literal) > 0 && !slices.Contains(values, t.literal) {
+			values = append(values, t.literal)
+		}
+	}
+	values = append([]string{freshValue(automatonA, automatonB, values)}, values...)
+
+	samples := []string{}
+	if n <= 0 {
+		return samples, nil
+	}
+	// the search is complete, a sample is found whenever b does not cover a
+	if witness, found := findPath(automatonA, automatonB,
+		func(inA, inB bool) bool { return inA && !inB },
+		func(deadA, deadB bool) bool { return deadA },
+	); found {
+		samples = append(samples, witness)
+	}
+	candidates := 0
+	// parts holds the text matched by each token of a
+	var expand func(i int, parts []string) bool
+	expand = func(i int, parts []string) bool {
+		if len(samples) >= n || candidates >= maxDifferenceCandidates {
+			return false
+		}
+		if i == len(tokensA) {
+			candidates++
+			path := joinTokenTexts(tokensA, parts, suffixA)
+			if automatonA.matches(path) && !automatonB.matches(path) &&
+				!slices.Contains(samples, path) {
+				samples = append(samples, path)
+			}
+			return true
+		}
+
+		switch tokensA[i].kind {
+		case tokenLiteral:
+			return expand(i+1, append(parts, tokensA[i].literal))
+		case tokenPathGlob:
+			for _, v := range values {
+				if !expand(i+1, append(slices.Clip(parts), v)) {
+					return false
+				}
+			}
+		case tokenTextGlob:
+			for count := 0; count <= maxTextGlobSegments; count++ {
+				next := func(segments []string) bool {
+					return expand(i+1, append(slices.Clip(parts), strings.Join(segments, "/")))
+				}
+				if !expandTextGlob(count, values, []string{}, next) {
+					return false
+				}
+			}
+		}
+		return true
+	}
+	expand(0, []string{})
+
+	return samples, nil
+}
+
+// freshValue returns a value made of a pchar found in no literal, prefix or suffix of a and b,
+// so it can't complete any of them, or a value distinct from the literals when there is none
+func freshValue(a, b *templateNFA, literals []string) string {
+	used := [256]bool{}
+	for _, c := range append(a.literalBytes(), b.literalBytes()...) {
+		used[c] = true
+	}
+	for _, c := range []byte("xyzabcdefghijklmnopqrstuvwXYZABCDEFGHIJKLMNOPQRSTUVW0123456789") {
+		if !used[c] {
+			return string(c)
+		}
+	}
+	fresh := "x"
+	for slices.Contains(literals, fresh) {
+		fresh += "x"
+	}
+	return fresh
+}
+
+// expandTextGlob calls next with segments extended by every combination of count values
+func expandTextGlob(count int, values []string, segments []string, next func([]string) bool) bool {
+	if count == 0 {
+		return next(segments)
+	}
+	for _, v := range values {
+		if !expandTextGlob(count-1, values, append(slices.Clip(segments), v), next) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,128 @@
+package path_template
+
+import (
+	"math/rand/v2"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestMatchDifferenceSample(t *testing.T) {
+	tt := []struct {
+		a, b    string
+		n       int
+		samples []string
+	}{
+		{
+			a:       "/users/*",
+			b:       "/users/admin",
+			n:       5,
+			samples: []string{"/users/u", "/users/x", "/users/users"},
+		},
+		{
+			a:       "/users/{id}",
+			b:       "/users/*",
+			n:       5,
+			samples: []string{},
+		},
+		{
+			a:       "/api/**",
+			b:       "/api/v1/**",
+			n:       3,
+			samples: []string{"/api/", "/api/x", "/api/api"},
+		},
+		{
+			a:       "/api/",
+			b:       "/api",
+			n:       3,
+			samples: []string{"/api/"},
+		},
+		{
+			a:       "/**",
+			b:       "/",
+			n:       2,
+			samples: []string{"//", "/x"},
+		},
+		{
+			a:       "/media/*",
+			b:       "/media/*.m3u8",
+			n:       1,
+			samples: []string{"/media/m"},
+		},
+		{
+			a:       "/media/*.m3u8",
+			b:       "/media/x.m3u8",
+			n:       2,
+			samples: []string{"/media/m.m3u8", "/media/y.m3u8"},
+		},
+		// fresh values must not complete the suffixes of b
+		{
+			a:       "/*",
+			b:       "/**x",
+			n:       2,
+			samples: []string{"/!", "/y"},
+		},
+		{
+			a:       "/*/*",
+			b:       "/**x",
+			n:       2,
+			samples: []string{"/x/!", "/y/y"},
+		},
+		{
+			a:       "/*",
+			b:       "/{p=**}x",
+			n:       2,
+			samples: []string{"/!", "/y"},
+		},
+	}
+	for _, tc := range tt {
+		samples, err := MatchDifferenceSample(tc.a, tc.b, tc.n)
+		assert.NilError(t, err)
+		assert.DeepEqual(t, samples, tc.samples)
+	}
+}
+
+// TestMatchDifferenceSampleCovers checks on random pairs that a sample is found exactly when b
+// does not cover a, and that samples are matched by a and not by b
+func TestMatchDifferenceSampleCovers(t *testing.T) {
+	segments := []string{"a", "x", "*", "**", "*x", "**x", "{p}", "{p=**}x"}
+	r := rand.New(rand.NewPCG(1, 2))
+	randomTemplate := func() string {
+		template := ""
+		for range 1 + r.IntN(3) {
+			template += "/" + segments[r.IntN(len(segments))]
+		}
+		return template
+	}
+	for range 2000 {
+		a, b := randomTemplate(), randomTemplate()
+		covers, err := Covers(b, a)
+		if err != nil {
+			continue
+		}
+		samples, err := MatchDifferenceSample(a, b, 3)
+		assert.NilError(t, err)
+		assert.Equal(t, len(samples) > 0, !covers, "%s %s", a, b)
+
+		tmplA, err := Parse(a)
+		assert.NilError(t, err)
+		tmplB, err := Parse(b)
+		assert.NilError(t, err)
+		for _, sample := range samples {
+			_, inA := tmplA.Match(sample)
+			_, inB := tmplB.Match(sample)
+			assert.Assert(t, inA && !inB, "%s %s %s", a, b, sample)
+		}
+	}
+}
+
+func TestMatchDifferenceSampleOptions(t *testing.T) {
+	samples, err := MatchDifferenceSample("/*-v1/*", "/*-v1/*.ts", 1, WithMultipleSuffixes())
+	assert.NilError(t, err)
+	assert.DeepEqual(t, samples, []string{"/--v1/-"})
+}
+
+func TestMatchDifferenceSampleInvalid(t *testing.T) {
+	_, err := MatchDifferenceSample("/a", "/b//c", 1)
+	assert.Error(t, err, "Empty segment not allowed in path template: b//c")
+}
@@ -0,0 +1,155 @@
+package path_template
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+const (
+	envoyMatcherName     = "envoy.path.match.uri_template.uri_template_matcher"
+	envoyMatcherTypeURL  = "type.googleapis.com/envoy.extensions.path.match.uri_template.v3.UriTemplateMatchConfig"
+	envoyRewriterName    = "envoy.path.rewrite.uri_template.uri_template_rewriter"
+	envoyRewriterTypeURL = "type.googleapis.com/envoy.extensions.path.rewrite.uri_template.v3.UriTemplateRewriteConfig"
+)
+
+// ToEnvoyRouteYAML validates a path template and its rewrite and renders them as an envoy route,
+// ready to be pasted under the routes of a virtual host.
+// The rewrite is optional, an empty rewrite renders a route without a path_rewrite_policy.
+func ToEnvoyRouteYAML(match, rewrite, cluster string) (string, error) {
+	variableNames, err := ValidatePathTemplate(match)
+	if err != nil {
+		return "", err
+	}
+	if len(rewrite) > 0 {
+		if err := ValidatePathTemplateRewrite(rewrite, variableNames); err != nil {
+			return "", err
+		}
+	}
+	if len(cluster) == 0 {
+		return "", fmt.Errorf("Cluster cannot be empty for route: %s", excerpt(match))
+	}
+	return renderEnvoyRoute(match, rewrite, cluster), nil
+}
+
+// renderEnvoyRoute renders a route without validating it
+func renderEnvoyRoute(match, rewrite, cluster string) string {
+	var sb strings.Builder
+	sb.WriteString("- match:\n")
+	sb.WriteString("    path_match_policy:\n")
+	fmt.Fprintf(&sb, "      name: %s\n", envoyMatcherName)
+	sb.WriteString("      typed_config:\n")
+	fmt.Fprintf(&sb, "        \"@type\": %s\n", envoyMatcherTypeURL)
+	fmt.Fprintf(&sb, "        path_template: %s\n", strconv.Quote(match))
+	sb.WriteString("  route:\n")
+	fmt.Fprintf(&sb, "    cluster: %s\n", strconv.Quote(cluster))
+	if len(rewrite) > 0 {
+		sb.WriteString("    path_rewrite_policy:\n")
+		fmt.Fprintf(&sb, "      name: %s\n", envoyRewriterName)
+		sb.WriteString("      typed_config:\n")
+		fmt.Fprintf(&sb, "        \"@type\": %s\n", envoyRewriterTypeURL)
+		fmt.Fprintf(&sb, "        path_template_rewrite: %s\n", strconv.Quote(rewrite))
+	}
+	return sb.String()
+}
+
+// EnvoyRoute is a uri_template route found in an envoy config
+type EnvoyRoute struct {
+	// Match is the path_template of the uri_template matcher
+	Match string
+	// Rewrite is the path_template_rewrite of the uri_template rewriter, if any
+	Rewrite string
+	// Err is the validation error of the match or the rewrite
+	Err error
+}
+
+// FromEnvoyConfig extracts the uri_template routes of an envoy bootstrap or RDS config in JSON form.
+// Every route is validated and returned along with its validation error, so invalid routes
+// already deployed can be audited. Routes are returned in config order.
+func FromEnvoyConfig(r io.Reader) ([]EnvoyRoute, error) {
+	return FromEnvoyConfigContext(context.Background(), r)
+}
+
+// FromEnvoyConfigContext is FromEnvoyConfig profiled with the pprof labels of ctx, see NewOverlapMatrixContext
+func FromEnvoyConfigContext(ctx context.Context, r io.Reader) ([]EnvoyRoute, error) {
+	var config any
+	if err := json.NewDecoder(r).Decode(&config); err != nil {
+		return nil, fmt.Errorf("Invalid envoy config: %w", err)
+	}
+
+	routes := []EnvoyRoute{}
+	profileOperation(ctx, "from-envoy-config", -1, func(context.Context) {
+		collectEnvoyRoutes(config, &routes)
+	})
+	return routes, nil
+}
+
+// collectEnvoyRoutes walks a decoded JSON value looking for routes matched by a uri_template
+func collectEnvoyRoutes(value any, routes *[]EnvoyRoute) {
+	switch v := value.(type) {
+	case []any:
+		for _, item := range v {
+			collectEnvoyRoutes(item, routes)
+		}
+	case map[string]any:
+		match, ok := lookupString(v, "match", "path_match_policy", "typed_config", "path_template")
+		if ok {
+			route := EnvoyRoute{Match: match}
+			route.Rewrite, _ = lookupString(v, "route", "path_rewrite_policy", "typed_config", "path_template_rewrite")
+
+			variableNames, err := ValidatePathTemplate(route.Match)
+			if err == nil && len(route.Rewrite) > 0 {
+				err = ValidatePathTemplateRewrite(route.Rewrite, variableNames)
+			}
+			route.Err = err
+			*routes = append(*routes, route)
+			return
+		}
+		// routes are always in lists, sorting only makes the walk deterministic
+		for _, key := range slices.Sorted(maps.Keys(v)) {
+			collectEnvoyRoutes(v[key], routes)
+		}
+	}
+}
+
+// lookupString follows keys through nested JSON objects and returns the string found at the end.
+// Keys are snake_case, their proto3 JSON camelCase spelling is accepted too, as found in config dumps.
+func lookupString(object map[string]any, keys ...string) (string, bool) {
+	var value any = object
+	for _, key := range keys {
+		o, ok := value.(map[string]any)
+		if !ok {
+			return "", false
+		}
+		v, found := o[key]
+		if !found {
+			v = o[camelCase(key)]
+		}
+		value = v
+	}
+	s, ok := value.(string)
+	return s, ok
+}
+
+// camelCase converts a snake_case key to its proto3 JSON spelling - path_template -> pathTemplate
+func camelCase(key string) string {
+	var sb strings.Builder
+	upper := false
+	for i := 0; i < len(key); i++ {
+		switch {
+		case key[i] == '_':
+			upper = true
+		case upper:
+			sb.WriteString(strings.ToUpper(key[i : i+1]))
+			upper = false
+		default:
+			sb.WriteByte(key[i])
+		}
+	}
+	return sb.String()
+}
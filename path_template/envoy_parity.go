@@ -0,0 +1,100 @@
+//go:build envoy
+
+package path_template
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+const parityCluster = "parity"
+
+// parityBootstrap is an envoy bootstrap config, the %s placeholder is replaced by its single route
+const parityBootstrap = `static_resources:
+  listeners:
+  - name: parity
+    address:
+      socket_address:
+        address: 127.0.0.1
+        port_value: 10000
+    filter_chains:
+    - filters:
+      - name: envoy.filters.network.http_connection_manager
+        typed_config:
+          "@type": type.googleapis.com/envoy.extensions.filters.network.http_connection_manager.v3.HttpConnectionManager
+          stat_prefix: parity
+          http_filters:
+          - name: envoy.filters.http.router
+            typed_config:
+              "@type": type.googleapis.com/envoy.extensions.filters.http.router.v3.Router
+          route_config:
+            virtual_hosts:
+            - name: parity
+              domains: ["*"]
+              routes:
+%s
+  clusters:
+  - name: parity
+    connect_timeout: 1s
+    type: STATIC
+    load_assignment:
+      cluster_name: parity
+`
+
+// ParityResult compares the decisions of ValidatePathTemplate and envoy on a path template
+type ParityResult struct {
+	Template string
+	// Err is the error of ValidatePathTemplate, nil when the template is accepted
+	Err error
+	// EnvoyAccepted is true when envoy loads a config routing with the template
+	EnvoyAccepted bool
+	// EnvoyOutput is the output of envoy, explaining why it rejected the template
+	EnvoyOutput string
+}
+
+// Agrees reports whether both validators accept or both reject the template
+func (r ParityResult) Agrees() bool {
+	return (r.Err == nil) == r.EnvoyAccepted
+}
+
+// EnvoyParity validates every template with ValidatePathTemplate and with the envoy binary found at
+// envoyBinary, running it in validate mode on a config routing with the template, and reports both decisions.
+// It is only built with the envoy build tag, as it needs an envoy binary.
+func EnvoyParity(ctx context.Context, envoyBinary string, templates []string) ([]ParityResult, error) {
+	dir, err := os.MkdirTemp("", "envoy-parity")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	results := make([]ParityResult, 0, len(templates))
+	for i, template := range templates {
+		_, validationErr := ValidatePathTemplate(template)
+
+		route := renderEnvoyRoute(template, "", parityCluster)
+		indented := "              " + strings.ReplaceAll(strings.TrimSuffix(route, "\n"), "\n", "\n              ")
+		config := filepath.Join(dir, fmt.Sprintf("route-%d.yaml", i))
+		if err := os.WriteFile(config, []byte(fmt.Sprintf(parityBootstrap, indented)), 0o600); err != nil {
+			return nil, err
+		}
+
+		output, err := exec.CommandContext(ctx, envoyBinary, "--mode", "validate", "-c", config).CombinedOutput()
+		if err != nil && ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		if _, ok := err.(*exec.ExitError); err != nil && !ok {
+			return nil, fmt.Errorf("Cannot run envoy: %w", err)
+		}
+		results = append(results, ParityResult{
+			Template:      template,
+			Err:           validationErr,
+			EnvoyAccepted: err == nil,
+			EnvoyOutput:   string(output),
+		})
+	}
+	return results, nil
+}
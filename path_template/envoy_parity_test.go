@@ -0,0 +1,46 @@
+//go:build envoy
+
+package path_template
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+// TestEnvoyParity compares our decisions with envoy's, run it with
+// ENVOY_BINARY=/path/to/envoy go test -tags envoy -run TestEnvoyParity
+func TestEnvoyParity(t *testing.T) {
+	envoyBinary := os.Getenv("ENVOY_BINARY")
+	if len(envoyBinary) == 0 {
+		envoyBinary = "envoy"
+	}
+	if _, err := exec.LookPath(envoyBinary); err != nil {
+		t.Skipf("Envoy binary not found, set ENVOY_BINARY: %s", err)
+	}
+
+	templates := []string{
+		"/",
+		"/api/{version}/users/{id}",
+		"/static/{path=**}",
+		"/{x=a/*}/**.ts",
+		"/a/{b}/{b}",
+		"/a/**/*",
+		"/a/{b=c*}",
+		"/{a}/{b}/{c}/{d}/{e}/{f}",
+		"/{abcdefghijklmnopq}",
+		"/a/*.ts/b",
+	}
+	results, err := EnvoyParity(context.Background(), envoyBinary, templates)
+	assert.NilError(t, err)
+
+	for _, r := range results {
+		t.Logf("%-30s ours: %-5t envoy: %t", r.Template, r.Err == nil, r.EnvoyAccepted)
+		if !r.Agrees() {
+			t.Errorf("Decisions differ for %s: %v\n%s", r.Template, r.Err, r.EnvoyOutput)
+		}
+	}
+}
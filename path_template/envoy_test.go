@@ -0,0 +1,188 @@
+package path_template
+
+import (
+	"strings"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestToEnvoyRouteYAML(t *testing.T) {
+	route, err := ToEnvoyRouteYAML("/api/{version}/{path=**}", "/{version}/{path}", "backend")
+	assert.NilError(t, err)
+	assert.Equal(t, route, `- match:
+    path_match_policy:
+      name: envoy.path.match.uri_template.uri_template_matcher
+      typed_config:
+        "@type": type.googleapis.com/envoy.extensions.path.match.uri_template.v3.UriTemplateMatchConfig
+        path_template: "/api/{version}/{path=**}"
+  route:
+    cluster: "backend"
+    path_rewrite_policy:
+      name: envoy.path.rewrite.uri_template.uri_template_rewriter
+      typed_config:
+        "@type": type.googleapis.com/envoy.extensions.path.rewrite.uri_template.v3.UriTemplateRewriteConfig
+        path_template_rewrite: "/{version}/{path}"
+`)
+}
+
+func TestToEnvoyRouteYAMLWithoutRewrite(t *testing.T) {
+	route, err := ToEnvoyRouteYAML("/static/**", "", "static")
+	assert.NilError(t, err)
+	assert.Equal(t, route, `- match:
+    path_match_policy:
+      name: envoy.path.match.uri_template.uri_template_matcher
+      typed_config:
+        "@type": type.googleapis.com/envoy.extensions.path.match.uri_template.v3.UriTemplateMatchConfig
+        path_template: "/static/**"
+  route:
+    cluster: "static"
+`)
+}
+
+func TestToEnvoyRouteYAMLFailure(t *testing.T) {
+	tt := []struct {
+		match   string
+		rewrite string
+		cluster string
+		err     string
+	}{
+		{
+			match:   "/a//b",
+			cluster: "backend",
+			err:     "Empty segment not allowed in path template: a//b",
+		},
+		{
+			match:   "/{a}",
+			rewrite: "/{b}",
+			cluster: "backend",
+			err:     "Variable b in path template rewrite is not present in the path template: /{b}",
+		},
+		{
+			match: "/{a}",
+			err:   "Cluster cannot be empty for route: /{a}",
+		},
+	}
+	for _, tc := range tt {
+		_, err := ToEnvoyRouteYAML(tc.match, tc.rewrite, tc.cluster)
+		assert.Error(t, err, tc.err)
+	}
+}
+
+func TestFromEnvoyConfig(t *testing.T) {
+	config := `{
+  "virtual_hosts": [
+    {
+      "name": "backend",
+      "domains": ["*"],
+      "routes": [
+        {
+          "match": {
+            "path_match_policy": {
+              "name": "envoy.path.match.uri_template.uri_template_matcher",
+              "typed_config": {
+                "@type": "type.googleapis.com/envoy.extensions.path.match.uri_template.v3.UriTemplateMatchConfig",
+                "path_template": "/api/{version}/{path=**}"
+              }
+            }
+          },
+          "route": {
+            "cluster": "backend",
+            "path_rewrite_policy": {
+              "name": "envoy.path.rewrite.uri_template.uri_template_rewriter",
+              "typed_config": {
+                "@type": "type.googleapis.com/envoy.extensions.path.rewrite.uri_template.v3.UriTemplateRewriteConfig",
+                "path_template_rewrite": "/{version}/{path}"
+              }
+            }
+          }
+        },
+        {
+          "match": {"prefix": "/health"},
+          "route": {"cluster": "backend"}
+        },
+        {
+          "match": {
+            "path_match_policy": {
+              "typed_config": {"path_template": "/static/**"}
+            }
+          },
+          "route": {"cluster": "static"}
+        },
+        {
+          "match": {
+            "path_match_policy": {
+              "typed_config": {"path_template": "/{a}"}
+            }
+          },
+          "route": {
+            "path_rewrite_policy": {
+              "typed_config": {"path_template_rewrite": "/{b}"}
+            }
+          }
+        }
+      ]
+    }
+  ]
+}`
+	routes, err := FromEnvoyConfig(strings.NewReader(config))
+	assert.NilError(t, err)
+	assert.Equal(t, len(routes), 3)
+
+	assert.Equal(t, routes[0].Match, "/api/{version}/{path=**}")
+	assert.Equal(t, routes[0].Rewrite, "/{version}/{path}")
+	assert.NilError(t, routes[0].Err)
+
+	assert.Equal(t, routes[1].Match, "/static/**")
+	assert.Equal(t, routes[1].Rewrite, "")
+	assert.NilError(t, routes[1].Err)
+
+	assert.Equal(t, routes[2].Match, "/{a}")
+	assert.Error(t, routes[2].Err, "Variable b in path template rewrite is not present in the path template: /{b}")
+}
+
+func TestFromEnvoyConfigCamelCase(t *testing.T) {
+	// as emitted by the /config_dump admin endpoint
+	config := `{
+  "configs": [
+    {
+      "dynamicRouteConfigs": [
+        {
+          "routeConfig": {
+            "virtualHosts": [
+              {
+                "routes": [
+                  {
+                    "match": {
+                      "pathMatchPolicy": {
+                        "name": "envoy.path.match.uri_template.uri_template_matcher",
+                        "typedConfig": {"pathTemplate": "/api/{version}/{path=**}"}
+                      }
+                    },
+                    "route": {
+                      "pathRewritePolicy": {
+                        "typedConfig": {"pathTemplateRewrite": "/{version}/{path}"}
+                      }
+                    }
+                  }
+                ]
+              }
+            ]
+          }
+        }
+      ]
+    }
+  ]
+}`
+	routes, err := FromEnvoyConfig(strings.NewReader(config))
+	assert.NilError(t, err)
+	assert.Equal(t, len(routes), 1)
+	assert.Equal(t, routes[0].Match, "/api/{version}/{path=**}")
+	assert.Equal(t, routes[0].Rewrite, "/{version}/{path}")
+	assert.NilError(t, routes[0].Err)
+}
+
+func TestFromEnvoyConfigInvalid(t *testing.T) {
+	_, err := FromEnvoyConfig(strings.NewReader("{"))
+	assert.Error(t, err, "Invalid envoy config: unexpected EOF")
+}
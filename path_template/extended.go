@@ -0,0 +1,73 @@
+package path_template
+
+import (
+	"slices"
+	"strings"
+)
+
+// The extended dialect lifts restrictions of envoy for in-process routers built on this package.
+// Templates using its extensions are not valid envoy path templates.
+
+// WithMultipleSuffixes allows suffixed operators anywhere in a path template, not only as the
+// final segment - /{a}-v1/{b}-v2. Part of DialectExtended.
+func WithMultipleSuffixes() Option {
+	return func(o *options) {
+		o.multipleSuffixes = true
+	}
+}
+
+// WithOperatorPrefixes allows literals before operators - /v{major}/img-*.png. The prefix is not
+// part of the value bound to a variable: v{major} binds 2 for v2. Part of DialectExtended.
+func WithOperatorPrefixes() Option {
+	return func(o *options) {
+		o.operatorPrefixes = true
+	}
+}
+
+// WithVariableReuse allows a variable to appear several times in a path template, with the same
+// pattern - /{id}/compare/{id}. A path is matched only when every occurrence binds the same value,
+// which no regular expression can check, so such templates are always KindComplex.
+// The variable is listed once. Part of DialectExtended.
+func WithVariableReuse() Option {
+	return func(o *options) {
+		o.variableReuse = true
+	}
+}
+
+// hasReusedVariable reports whether a variable appears more than once in a parsed template
+func hasReusedVariable(parsed *ParsedTemplate) bool {
+	names := []string{}
+	for _, node := range parsed.Segments {
+		if node.Kind != NodeVariable {
+			continue
+		}
+		if slices.Contains(names, node.Name) {
+			return true
+		}
+		names = append(names, node.Name)
+	}
+	return false
+}
+
+// WithOptionalSegments allows the final segments of a path template to be optional, enclosed in
+// brackets - /users/{id}[/{format}] matches both /users/42 and /users/42/json, leaving format
+// unbound for the former. The template is validated with the brackets removed, /users/{id}/{format}.
+// Part of DialectExtended.
+func WithOptionalSegments() Option {
+	return func(o *options) {
+		o.optionalSegments = true
+	}
+}
+
+// splitOptionalSegments splits a path template ending with optional segments into the required
+// segments and the optional ones, without the brackets: /a[/b/c] -> /a and /b/c
+func (o *options) splitOptionalSegments(path string) (string, string, bool) {
+	if !o.optionalSegments || !strings.HasSuffix(path, "]") {
+		return "", "", false
+	}
+	i := strings.Index(path, "[/")
+	if i <= 0 {
+		return "", "", false
+	}
+	return path[:i], path[i+1 : len(path)-1], true
+}
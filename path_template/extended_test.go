@@ -0,0 +1,303 @@
+package path_template
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestWithMultipleSuffixes(t *testing.T) {
+	tt := []struct {
+		path     string
+		opts     []Option
+		expected []string
+		err      string
+	}{
+		// not final suffixed operator
+		{
+			path: "/{a}-v1/{b}-v2",
+			err:  "The suffixed operator must in be the final path component: /{a}-v1/{b}-v2",
+		},
+		// multiple suffixes
+		{
+			path:     "/{a}-v1/{b}-v2",
+			opts:     []Option{WithMultipleSuffixes()},
+			expected: []string{"a", "b"},
+		},
+		// extended dialect
+		{
+			path:     "/*.d/{a=**}.ts",
+			opts:     []Option{WithDialect(DialectExtended)},
+			expected: []string{"a"},
+		},
+		// suffix variable of the final segment
+		{
+			path:     "/*.d/{a}.ts",
+			opts:     []Option{WithMultipleSuffixes(), WithSuffixVariable()},
+			expected: []string{"a", SuffixVariableName},
+		},
+		// invalid suffix
+		{
+			path: "/{a}-{b}/c",
+			opts: []Option{WithMultipleSuffixes()},
+			err:  "Variable name must start with a letter",
+		},
+	}
+	for _, tc := range tt {
+		names, err := ValidatePathTemplate(tc.path, tc.opts...)
+		if tc.err != "" {
+			assert.ErrorContains(t, err, tc.err)
+			continue
+		}
+		assert.NilError(t, err)
+		assert.DeepEqual(t, names, tc.expected)
+
+		_, diagnostics := ParseLenient(tc.path, tc.opts...)
+		assert.Equal(t, len(diagnostics), 0)
+		kind, err := Classify(tc.path, tc.opts...)
+		assert.NilError(t, err)
+		assert.Equal(t, kind, KindComplex)
+	}
+}
+
+func TestMultipleSuffixesAutomaton(t *testing.T) {
+	parsed, err := parseValid("/{a}-v1/{b=**}.ts", []Option{WithMultipleSuffixes()})
+	assert.NilError(t, err)
+	automaton := templateAutomaton(parsed)
+	for path, match := range map[string]bool{
+		"/x-v1/y.ts":     true,
+		"/x-v1/y/z.ts":   true,
+		"/x-v1/.ts":      true,
+		"/-v1/y.ts":      false,
+		"/x/y.ts":        false,
+		"/x-v1/y":        false,
+		"/x-v1-v1/y.ts":  true,
+		"/x-v1/y-v1.ts/": false,
+	} {
+		assert.Equal(t, automaton.matches(path), match, path)
+	}
+}
+
+func TestWithOperatorPrefixes(t *testing.T) {
+	tt := []struct {
+		path     string
+		opts     []Option
+		expected []string
+		err      string
+	}{
+		// prefixed variable
+		{
+			path: "/v{major}",
+			err:  "Prefixes not allowed before operators: v{major}",
+		},
+		// operator prefixes
+		{
+			path:     "/api/v{major}/img-*.png",
+			opts:     []Option{WithOperatorPrefixes()},
+			expected: []string{"major"},
+		},
+		// extended dialect
+		{
+			path:     "/v{major}/files-{path=**}",
+			opts:     []Option{WithDialect(DialectExtended)},
+			expected: []string{"major", "path"},
+		},
+		// prefixed operator in a variable pattern
+		{
+			path: "/{a=v*}",
+			opts: []Option{WithOperatorPrefixes()},
+			err:  "Prefixes or suffixes not allowed with variable pattern operators: v*",
+		},
+		// strict prefix
+		{
+			path: "/a=b{c}",
+			opts: []Option{WithOperatorPrefixes(), WithStrictRFC3986()},
+			err:  "Bare = not allowed in literal in strict RFC 3986 mode: a=b",
+		},
+	}
+	for _, tc := range tt {
+		names, err := ValidatePathTemplate(tc.path, tc.opts...)
+		if tc.err != "" {
+			assert.ErrorContains(t, err, tc.err)
+			continue
+		}
+		assert.NilError(t, err)
+		assert.DeepEqual(t, names, tc.expected)
+
+		kind, err := Classify(tc.path, tc.opts...)
+		assert.NilError(t, err)
+		assert.Equal(t, kind, KindComplex)
+	}
+}
+
+func TestOperatorPrefixesParse(t *testing.T) {
+	parsed, diagnostics := ParseLenient("/v{major}/img-*.png", WithOperatorPrefixes())
+	assert.Equal(t, len(diagnostics), 0)
+	assert.DeepEqual(t, parsed.Segments, []Node{
+		{Kind: NodeVariable, Range: Range{Start: 1, End: 9}, Text: "v{major}", Name: "major", Prefix: "v"},
+		{Kind: NodePathGlob, Range: Range{Start: 10, End: 19}, Text: "img-*.png", Prefix: "img-", Suffix: ".png"},
+	})
+}
+
+func TestOperatorPrefixesAutomaton(t *testing.T) {
+	parsed, err := parseValid("/v{major}/img-{path=**}", []Option{WithOperatorPrefixes()})
+	assert.NilError(t, err)
+	automaton := templateAutomaton(parsed)
+	for path, match := range map[string]bool{
+		"/v2/img-a":     true,
+		"/v2/img-a/b":   true,
+		"/v2/img-":      true,
+		"/v/img-a":      false,
+		"/2/img-a":      false,
+		"/v2/a":         false,
+		"/vv2/img-img-": true,
+	} {
+		assert.Equal(t, automaton.matches(path), match, path)
+	}
+}
+
+func TestWithVariableReuse(t *testing.T) {
+	tt := []struct {
+		path     string
+		opts     []Option
+		expected []string
+		err      string
+	}{
+		// duplicated variable
+		{
+			path: "/{id}/compare/{id}",
+			err:  "Variable name is duplicated: id",
+		},
+		// reused variable
+		{
+			path:     "/{id}/compare/{id}",
+			opts:     []Option{WithVariableReuse()},
+			expected: []string{"id"},
+		},
+		// extended dialect
+		{
+			path:     "/{a=x/*}/{b}/{a=x/*}",
+			opts:     []Option{WithDialect(DialectExtended)},
+			expected: []string{"a", "b"},
+		},
+		// different patterns
+		{
+			path: "/{id}/{id=x/*}",
+			opts: []Option{WithVariableReuse()},
+			err:  "Reused variable must have the same pattern: id",
+		},
+		// reused text glob
+		{
+			path: "/{a=**}/{a=**}",
+			opts: []Option{WithVariableReuse()},
+			err:  "Cannot have variable after text glob (**): {a=**}",
+		},
+	}
+	for _, tc := range tt {
+		names, err := ValidatePathTemplate(tc.path, tc.opts...)
+		if tc.err != "" {
+			assert.ErrorContains(t, err, tc.err)
+			continue
+		}
+		assert.NilError(t, err)
+		assert.DeepEqual(t, names, tc.expected)
+
+		kind, err := Classify(tc.path, tc.opts...)
+		assert.NilError(t, err)
+		assert.Equal(t, kind, KindComplex)
+	}
+}
+
+func TestWithOptionalSegments(t *testing.T) {
+	tt := []struct {
+		path     string
+		opts     []Option
+		expected []string
+		err      string
+	}{
+		// optional segment
+		{
+			path: "/users/{id}[/{format}]",
+			err:  "Invalid segment in path template: {id}[",
+		},
+		// optional segments
+		{
+			path:     "/users/{id}[/{format}]",
+			opts:     []Option{WithOptionalSegments()},
+			expected: []string{"id", "format"},
+		},
+		// extended dialect
+		{
+			path:     "/a[/b/**]",
+			opts:     []Option{WithDialect(DialectExtended)},
+			expected: []string{},
+		},
+		// suffixed required segment
+		{
+			path: "/a/*.ts[/b]",
+			opts: []Option{WithOptionalSegments()},
+			err:  "The suffixed operator must in be the final path component: /a/*.ts/b",
+		},
+		// several optional groups
+		{
+			path: "/a[/b][/c]",
+			opts: []Option{WithOptionalSegments()},
+			err:  "Invalid segment in path template: b][",
+		},
+	}
+	for _, tc := range tt {
+		names, err := ValidatePathTemplate(tc.path, tc.opts...)
+		if tc.err != "" {
+			assert.ErrorContains(t, err, tc.err)
+			continue
+		}
+		assert.NilError(t, err)
+		assert.DeepEqual(t, names, tc.expected)
+
+		kind, err := Classify(tc.path, tc.opts...)
+		assert.NilError(t, err)
+		assert.Equal(t, kind, KindComplex)
+	}
+}
+
+func TestOptionalSegmentsParse(t *testing.T) {
+	parsed, diagnostics := ParseLenient("/users/{id}[/{format}/x]", WithOptionalSegments())
+	assert.Equal(t, len(diagnostics), 0)
+	assert.Equal(t, parsed.Text, "/users/{id}[/{format}/x]")
+	assert.Equal(t, parsed.Optional, 2)
+	assert.DeepEqual(t, parsed.Segments[2], Node{Kind: NodeVariable, Range: Range{Start: 13, End: 21}, Text: "{format}", Name: "format"})
+	assert.DeepEqual(t, parsed.Segments[3], Node{Kind: NodeLiteral, Range: Range{Start: 22, End: 23}, Text: "x"})
+
+	_, diagnostics = ParseLenient("/a[/b=]", WithOptionalSegments(), WithStrictRFC3986())
+	assert.Equal(t, len(diagnostics), 1)
+	assert.DeepEqual(t, diagnostics[0].Range, Range{Start: 4, End: 6})
+}
+
+func TestOptionalSegmentsAutomaton(t *testing.T) {
+	paths := []string{"/users", "/users/42", "/users/42/", "/users/42/json", "/users/42/json/", "/users/42/json/x", "/users/42/json/x/", "/a", "/a/b/c"}
+	for _, template := range []string{"/users/{id}[/{format}]", "/users/{id}[/{format}/x]", "/users/{id}[/{format}/]", "/a[/b/**]"} {
+		tmpl, err := Parse(template, WithOptionalSegments())
+		assert.NilError(t, err)
+		parsed, err := parseValid(template, []Option{WithOptionalSegments()})
+		assert.NilError(t, err)
+		automaton := templateAutomaton(parsed)
+		for _, path := range paths {
+			_, match := tmpl.Match(path)
+			assert.Equal(t, automaton.matches(path), match, "%s %s", template, path)
+		}
+	}
+
+	covers, err := Covers("/users/{id}[/{format}]", "/users/*", WithOptionalSegments())
+	assert.NilError(t, err)
+	assert.Assert(t, covers)
+	covers, err = Covers("/users/*/*", "/users/{id}[/{format}]", WithOptionalSegments())
+	assert.NilError(t, err)
+	assert.Assert(t, !covers)
+	equivalent, err := Equivalent("/a[/**]", "/a/**", WithOptionalSegments())
+	assert.NilError(t, err)
+	assert.Assert(t, !equivalent)
+
+	samples, err := MatchDifferenceSample("/users/{id}[/{format}]", "/users/*/*", 1, WithOptionalSegments())
+	assert.NilError(t, err)
+	assert.DeepEqual(t, samples, []string{"/users/u"})
+}
@@ -0,0 +1,107 @@
+package path_template
+
+import "strings"
+
+type formatOptions struct {
+	width int
+	ascii bool
+	// validation holds the options the error was found with
+	validation []Option
+}
+
+// FormatOption configures FormatError
+type FormatOption func(*formatOptions)
+
+// WithWidth limits rendered lines to width columns, long templates are cut around the offending span.
+// Lines are not limited when width is 0 or less.
+func WithWidth(width int) FormatOption {
+	return func(o *formatOptions) {
+		o.width = width
+	}
+}
+
+// WithASCII renders with ASCII characters only, for terminals and logs without unicode support
+func WithASCII() FormatOption {
+	return func(o *formatOptions) {
+		o.ascii = true
+	}
+}
+
+// WithValidationOptions gives the options template was validated with, so errors which only occur
+// with them, ie limits or strict mode, are located
+func WithValidationOptions(opts ...Option) FormatOption {
+	return func(o *formatOptions) {
+		o.validation = opts
+	}
+}
+
+// FormatError renders a validation error of template on two lines: the template and
+// a caret underlining the offending span, followed by the error message.
+// Joined errors are rendered one after another. Errors which can't be located in template,
+// such as the errors of a path template rewrite, are rendered as their message only.
+// Errors found with validation options are located with WithValidationOptions.
+//
+//	/a/{b}/{b}
+//	       ^── Variable name is duplicated: b
+func FormatError(err error, template string, opts ...FormatOption) string {
+	if err == nil {
+		return ""
+	}
+	o := &formatOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	errs := []error{err}
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		errs = joined.Unwrap()
+	}
+
+	_, diagnostics := ParseLenient(template, o.validation...)
+	rendered := make([]string, 0, len(errs))
+	for _, e := range errs {
+		located := false
+		for _, d := range diagnostics {
+			if d.Message == e.Error() {
+				rendered = append(rendered, formatDiagnostic(d, template, o))
+				located = true
+				break
+			}
+		}
+		if !located {
+			rendered = append(rendered, e.Error())
+		}
+	}
+	return strings.Join(rendered, "\n")
+}
+
+// formatDiagnostic renders a diagnostic of template on two lines
+func formatDiagnostic(d Diagnostic, template string, o *formatOptions) string {
+	ellipsis, underline := "…", "─"
+	if o.ascii {
+		ellipsis, underline = "...", "~"
+	}
+	ellipsisWidth := len([]rune(ellipsis))
+
+	// the window of the template which is displayed
+	start, end := 0, len(template)
+	if o.width > 0 && len(template) > o.width {
+		room := max(o.width-2*ellipsisWidth, 1)
+		start = max(0, min(d.Range.Start-room/2, len(template)-room))
+		end = min(len(template), start+room)
+	}
+
+	line := template[start:end]
+	offset := d.Range.Start - start
+	if start > 0 {
+		line = ellipsis + line
+		offset += ellipsisWidth
+	}
+	if end < len(template) {
+		line += ellipsis
+	}
+
+	spanEnd := min(d.Range.End, end)
+	marker := "^" + strings.Repeat(underline, max(spanEnd-d.Range.Start-1, 0))
+	return line + "\n" + strings.Repeat(" ", offset) + marker + " " + d.Message
+}
@@ -0,0 +1,69 @@
+package path_template
+
+import (
+	"errors"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestFormatError(t *testing.T) {
+	tt := []struct {
+		template   string
+		validation []Option
+		opts       []FormatOption
+		expected   string
+	}{
+		{
+			template: "/a/{b}/{b}",
+			expected: "/a/{b}/{b}\n       ^── Variable name is duplicated: b",
+		},
+		{
+			template: "/a/{b}/{b}",
+			opts:     []FormatOption{WithASCII()},
+			expected: "/a/{b}/{b}\n       ^~~ Variable name is duplicated: b",
+		},
+		{
+			template: "a/b",
+			expected: "a/b\n^ PathTemplate must start with a /: a/b",
+		},
+		{
+			template: "/aaaaaaaaaaaaaaaaaaaaaaaaaaaaaa/{b}/{b}/cccccccccccccccccccccccc",
+			opts:     []FormatOption{WithWidth(30), WithASCII()},
+			expected: "...aaaaaaa/{b}/{b}/cccccccc...\n               ^~~ Variable name is duplicated: b",
+		},
+		{
+			template: "/aaaaaaaaaaaaaaaaaaaaaaaaaaaaaa/{b}/{b}",
+			opts:     []FormatOption{WithWidth(20)},
+			expected: "…aaaaaaaaaa/{b}/{b}\n                ^── Variable name is duplicated: b",
+		},
+		{
+			template:   "/{a}/{b}",
+			validation: []Option{WithLimits(ValidationLimits{MaxVariables: 1})},
+			expected:   "/{a}/{b}\n     ^── Cannot have more than 1 variables: /{a}/{b}",
+		},
+		{
+			template:   "/a=b",
+			validation: []Option{WithDialect(DialectStrict), WithErrorPrefix("route")},
+			expected:   "/a=b\n ^── route: Bare = not allowed in literal in strict RFC 3986 mode: a=b",
+		},
+	}
+	for _, tc := range tt {
+		_, err := ValidatePathTemplate(tc.template, tc.validation...)
+		opts := append(tc.opts, WithValidationOptions(tc.validation...))
+		assert.Equal(t, FormatError(err, tc.template, opts...), tc.expected)
+	}
+}
+
+func TestFormatErrorJoined(t *testing.T) {
+	template := "/{a}/{a}/{b=c*}"
+	_, err := ValidatePathTemplate(template, WithCollectAllErrors())
+	assert.Equal(t, FormatError(err, template, WithASCII()),
+		"/{a}/{a}/{b=c*}\n     ^~~ Variable name is duplicated: a\n"+
+			"/{a}/{a}/{b=c*}\n         ^~~~~~ Prefixes or suffixes not allowed with variable pattern operators: c*")
+}
+
+func TestFormatErrorUnlocated(t *testing.T) {
+	assert.Equal(t, FormatError(nil, "/a"), "")
+	assert.Equal(t, FormatError(errors.New("Some error"), "/a"), "Some error")
+}
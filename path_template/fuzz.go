@@ -0,0 +1,83 @@
+package path_template
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// FuzzCorpusOptions configures FuzzCorpus
+type FuzzCorpusOptions struct {
+	// ScrubLiterals replaces the literals, suffixes and variable names of the templates with
+	// placeholders, keeping their shape without leaking the names of internal services.
+	// Equal literals and equal variable names get the same placeholder.
+	ScrubLiterals bool
+}
+
+// FuzzCorpus converts path templates, typically the routes of a real config, into deduplicated
+// entries of a go fuzzing seed corpus, so the validator can be fuzzed with representative shapes.
+// Entries are keyed by file name and are meant to be written under testdata/fuzz/FuzzValidatePathTemplate.
+func FuzzCorpus(templates []string, opts FuzzCorpusOptions) (map[string][]byte, error) {
+	corpus := map[string][]byte{}
+	literals, names := map[string]string{}, map[string]string{}
+	for _, template := range templates {
+		if opts.ScrubLiterals {
+			parsed, err := parseValid(template, nil)
+			if err != nil {
+				return nil, err
+			}
+			template = scrubLiterals(parsed, literals, names)
+		}
+		entry := []byte("go test fuzz v1\nstring(" + strconv.Quote(template) + ")\n")
+		corpus[fmt.Sprintf("%x", sha256.Sum256(entry))[:16]] = entry
+	}
+	return corpus, nil
+}
+
+// scrubLiterals renders a valid parsed template with its literals, including those of variable patterns
+// and suffixes, replaced by placeholders, and its variable names replaced by placeholder names.
+// Placeholders are recorded in literals and names so equal literals and names are scrubbed alike.
+func scrubLiterals(parsed *ParsedTemplate, literals, names map[string]string) string {
+	placeholder := func(placeholders map[string]string, prefix, text string) string {
+		if _, ok := placeholders[text]; !ok {
+			placeholders[text] = prefix + strconv.Itoa(len(placeholders)+1)
+		}
+		return placeholders[text]
+	}
+	scrub := func(literal string) string {
+		if len(literal) == 0 {
+			return ""
+		}
+		return placeholder(literals, "l", literal)
+	}
+
+	segments := make([]string, 0, len(parsed.Segments))
+	for _, node := range parsed.Segments {
+		switch node.Kind {
+		case NodeLiteral:
+			segments = append(segments, scrub(node.Text))
+		case NodeVariable:
+			name := placeholder(names, "v", node.Name)
+			if len(node.Pattern) == 0 {
+				segments = append(segments, "{"+name+"}"+scrub(node.Suffix))
+				continue
+			}
+			pattern := strings.Split(node.Pattern, "/")
+			for i, patternSegment := range pattern {
+				if patternSegment != textGlob && patternSegment != pathGlob {
+					pattern[i] = scrub(patternSegment)
+				}
+			}
+			segments = append(segments, "{"+name+"="+strings.Join(pattern, "/")+"}"+scrub(node.Suffix))
+		default:
+			segments = append(segments, strings.TrimSuffix(node.Text, node.Suffix)+scrub(node.Suffix))
+		}
+	}
+
+	scrubbed := "/" + strings.Join(segments, "/")
+	if len(segments) > 0 && strings.HasSuffix(parsed.Text, "/") {
+		scrubbed += "/"
+	}
+	return scrubbed
+}
@@ -0,0 +1,64 @@
+package path_template
+
+import (
+	"slices"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestFuzzCorpus(t *testing.T) {
+	corpus, err := FuzzCorpus([]string{"/api/{id}", "/api/{id}", "/internal/billing/**"}, FuzzCorpusOptions{})
+	assert.NilError(t, err)
+	entries := []string{}
+	for name, entry := range corpus {
+		assert.Equal(t, len(name), 16)
+		entries = append(entries, string(entry))
+	}
+	slices.Sort(entries)
+	assert.DeepEqual(t, entries, []string{
+		"go test fuzz v1\nstring(\"/api/{id}\")\n",
+		"go test fuzz v1\nstring(\"/internal/billing/**\")\n",
+	})
+}
+
+func TestFuzzCorpusScrubLiterals(t *testing.T) {
+	tt := []struct {
+		templates []string
+		expected  []string
+	}{
+		{
+			templates: []string{"/internal/billing/{id}", "/internal/{x=billing/*}/invoices/**.pdf"},
+			expected:  []string{"/l1/l2/{v1}", "/l1/{v2=l2/*}/l3/**l4"},
+		},
+		{
+			templates: []string{"/docs/{name}.pdf", "/{name}/*.pdf"},
+			expected:  []string{"/l1/{v1}l2", "/{v1}/*l2"},
+		},
+		{
+			templates: []string{"/", "/a/", "/b/"},
+			expected:  []string{"/", "/l1/", "/l2/"},
+		},
+		{
+			// same shape once scrubbed
+			templates: []string{"/a/*", "/a/*"},
+			expected:  []string{"/l1/*"},
+		},
+	}
+	for _, tc := range tt {
+		corpus, err := FuzzCorpus(tc.templates, FuzzCorpusOptions{ScrubLiterals: true})
+		assert.NilError(t, err)
+		expected := map[string][]byte{}
+		for _, template := range tc.expected {
+			want, err := FuzzCorpus([]string{template}, FuzzCorpusOptions{})
+			assert.NilError(t, err)
+			for name, entry := range want {
+				expected[name] = entry
+			}
+		}
+		assert.DeepEqual(t, corpus, expected)
+	}
+
+	_, err := FuzzCorpus([]string{"/{a"}, FuzzCorpusOptions{ScrubLiterals: true})
+	assert.ErrorContains(t, err, "")
+}
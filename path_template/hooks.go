@@ -0,0 +1,82 @@
+package path_template
+
+import (
+	"context"
+	"runtime/trace"
+)
+
+// Hooks are called around the validation of a path template and the matching of request paths
+// by a parsed Template, so the time spent at scale can be attributed in the caller's own
+// profiles and traces
+type Hooks struct {
+	// OnParseStart is called before a path template is validated
+	OnParseStart func(template string)
+	// OnParseEnd is called once a path template is validated, with the validation error
+	OnParseEnd func(template string, err error)
+	// OnMatchStart is called before a request path is matched by a Template
+	OnMatchStart func(template, requestPath string)
+	// OnMatchEnd is called once a request path is matched by a Template, with the result
+	OnMatchEnd func(template, requestPath string, match bool)
+}
+
+// WithHooks calls hooks around every path template validation and request path match
+func WithHooks(hooks Hooks) Option {
+	return func(o *options) {
+		o.hooks = hooks
+	}
+}
+
+// WithTraceRegions runs every path template validation in a runtime/trace region of ctx
+// named path_template.Parse, and every request path match in one named path_template.Match,
+// visible in go tool trace
+func WithTraceRegions(ctx context.Context) Option {
+	return func(o *options) {
+		o.traceContext = ctx
+	}
+}
+
+// startParse calls the hooks starting the validation of template and returns the function ending it
+func (o *options) startParse(template string) func(err error) {
+	if o.hooks.OnParseStart == nil && o.hooks.OnParseEnd == nil && o.traceContext == nil {
+		return func(error) {}
+	}
+
+	var region *trace.Region
+	if o.traceContext != nil {
+		region = trace.StartRegion(o.traceContext, "path_template.Parse")
+	}
+	if o.hooks.OnParseStart != nil {
+		o.hooks.OnParseStart(template)
+	}
+	return func(err error) {
+		if o.hooks.OnParseEnd != nil {
+			o.hooks.OnParseEnd(template, err)
+		}
+		if region != nil {
+			region.End()
+		}
+	}
+}
+
+// startMatch calls the hooks starting the match of requestPath and returns the function ending it
+func (t *Template) startMatch(requestPath string) func(match bool) {
+	if t.hooks.OnMatchStart == nil && t.hooks.OnMatchEnd == nil && t.traceContext == nil {
+		return func(bool) {}
+	}
+
+	var region *trace.Region
+	if t.traceContext != nil {
+		region = trace.StartRegion(t.traceContext, "path_template.Match")
+	}
+	if t.hooks.OnMatchStart != nil {
+		t.hooks.OnMatchStart(t.text, requestPath)
+	}
+	return func(match bool) {
+		if t.hooks.OnMatchEnd != nil {
+			t.hooks.OnMatchEnd(t.text, requestPath, match)
+		}
+		if region != nil {
+			region.End()
+		}
+	}
+}
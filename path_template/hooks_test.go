@@ -0,0 +1,94 @@
+package path_template
+
+import (
+	"bytes"
+	"context"
+	"runtime/trace"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestHooks(t *testing.T) {
+	events := []string{}
+	hooks := WithHooks(Hooks{
+		OnParseStart: func(template string) {
+			events = append(events, "start "+template)
+		},
+		OnParseEnd: func(template string, err error) {
+			if err != nil {
+				events = append(events, "end "+template+": "+err.Error())
+			} else {
+				events = append(events, "end "+template)
+			}
+		},
+	})
+
+	_, err := ValidatePathTemplate("/a/{b}", hooks)
+	assert.NilError(t, err)
+	_, err = ValidatePathTemplate("/a/{b}/{b}", hooks)
+	assert.ErrorContains(t, err, "")
+	// analyses validate once
+	_, err = Classify("/c/*", hooks)
+	assert.NilError(t, err)
+
+	assert.DeepEqual(t, events, []string{
+		"start /a/{b}",
+		"end /a/{b}",
+		"start /a/{b}/{b}",
+		"end /a/{b}/{b}: Variable name is duplicated: b",
+		"start /c/*",
+		"end /c/*",
+	})
+}
+
+func TestMatchHooks(t *testing.T) {
+	events := []string{}
+	hooks := WithHooks(Hooks{
+		OnMatchStart: func(template, requestPath string) {
+			events = append(events, "start "+template+" "+requestPath)
+		},
+		OnMatchEnd: func(template, requestPath string, match bool) {
+			if match {
+				events = append(events, "match "+template+" "+requestPath)
+			} else {
+				events = append(events, "no match "+template+" "+requestPath)
+			}
+		},
+	})
+
+	tmpl, err := Parse("/a/{b}", hooks)
+	assert.NilError(t, err)
+	_, match := tmpl.Match("/a/x")
+	assert.Assert(t, match)
+	_, match = tmpl.MatchBytes([]byte("/b/x"))
+	assert.Assert(t, !match)
+
+	assert.DeepEqual(t, events, []string{
+		"start /a/{b} /a/x",
+		"match /a/{b} /a/x",
+		"start /a/{b} /b/x",
+		"no match /a/{b} /b/x",
+	})
+}
+
+func TestPartialHooks(t *testing.T) {
+	started := 0
+	_, err := ValidatePathTemplate("/a", WithHooks(Hooks{OnParseStart: func(string) { started++ }}))
+	assert.NilError(t, err)
+	assert.Equal(t, started, 1)
+}
+
+func TestTraceRegions(t *testing.T) {
+	if trace.IsEnabled() {
+		t.Skip("tracing already enabled")
+	}
+	var buf bytes.Buffer
+	assert.NilError(t, trace.Start(&buf))
+	tmpl, err := Parse("/a/{b}", WithTraceRegions(context.Background()))
+	assert.NilError(t, err)
+	tmpl.Match("/a/x")
+	trace.Stop()
+	assert.Assert(t, bytes.Contains(buf.Bytes(), []byte("path_template.Parse")))
+	assert.Assert(t, bytes.Contains(buf.Bytes(), []byte("path_template.Match")))
+}
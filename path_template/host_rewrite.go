@@ -0,0 +1,64 @@
+package path_template
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// ValidateHostRewriteTemplate validates a host rewrite template, the upstream authority of a route
+// built from the variables of its path template - {tenant}.internal.svc or {region}-{tenant}.svc:8080.
+// Like envoy's host_rewrite_path_regex, but with template variables. Variable names not present
+// in the path template are not allowed, a variable may be referenced several times.
+func ValidateHostRewriteTemplate(hostRewrite string, variableNames []string, opts ...Option) error {
+	o := newOptions(opts)
+	return o.reportError(validateHostRewriteTemplate(hostRewrite, variableNames, o))
+}
+
+func validateHostRewriteTemplate(hostRewrite string, variableNames []string, o *options) error {
+	if err := o.checkLength("Host rewrite", hostRewrite); err != nil {
+		return err
+	}
+	references, err := scanHostTemplate(hostRewrite, o)
+	if err != nil {
+		return err
+	}
+	for _, name := range references {
+		if !slices.Contains(variableNames, name) {
+			return fmt.Errorf("Variable %s in host rewrite is not present in the path template: %s", excerpt(name), excerpt(hostRewrite))
+		}
+	}
+	return nil
+}
+
+// RewriteHost expands a host rewrite template with the values bound to the variables of a
+// path template. The result must be a valid host: a value such as a/b, bound by a variable with
+// a text glob, is rejected rather than producing an invalid authority.
+func RewriteHost(hostRewrite string, bindings map[string]string) (string, error) {
+	if _, err := scanHostTemplate(hostRewrite, newOptions(nil)); err != nil {
+		return "", err
+	}
+
+	var sb strings.Builder
+	rest := hostRewrite
+	for len(rest) > 0 {
+		start := strings.IndexRune(rest, '{')
+		if start < 0 {
+			sb.WriteString(rest)
+			break
+		}
+		end := start + strings.IndexRune(rest[start:], '}')
+		name := rest[start+1 : end]
+		value, ok := bindings[name]
+		if !ok {
+			return "", fmt.Errorf("Variable %s in host rewrite is not bound: %s", excerpt(name), excerpt(hostRewrite))
+		}
+		if len(value) == 0 || !reURLHostLiteral.MatchString(value) {
+			return "", fmt.Errorf("Value of variable %s is not valid in a host: %s", excerpt(name), excerpt(value))
+		}
+		sb.WriteString(rest[:start])
+		sb.WriteString(value)
+		rest = rest[end+1:]
+	}
+	return sb.String(), nil
+}
@@ -0,0 +1,64 @@
+package path_template
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestValidateHostRewriteTemplate(t *testing.T) {
+	variableNames, err := ValidatePathTemplate("/{tenant}/{region}/{path=**}")
+	assert.NilError(t, err)
+
+	tt := []struct {
+		hostRewrite string
+		err         string
+	}{
+		{hostRewrite: "{tenant}.internal.svc"},
+		{hostRewrite: "{region}-{tenant}.svc:8080"},
+		{hostRewrite: "{tenant}.{tenant}.svc"},
+		{hostRewrite: "static.svc"},
+		{hostRewrite: "{other}.svc", err: "Variable other in host rewrite is not present in the path template: {other}.svc"},
+		{hostRewrite: "", err: "Host cannot be empty"},
+		{hostRewrite: "{tenant}.svc:http", err: "Port must be numeric: http"},
+		{hostRewrite: "{tenant.svc", err: "Unmatched { not allowed in host: {tenant.svc"},
+		{hostRewrite: "{tenant}_x.svc", err: "Invalid character in host: {tenant}_x.svc"},
+	}
+	for _, tc := range tt {
+		err := ValidateHostRewriteTemplate(tc.hostRewrite, variableNames)
+		if len(tc.err) == 0 {
+			assert.NilError(t, err, tc.hostRewrite)
+		} else {
+			assert.Error(t, err, tc.err, tc.hostRewrite)
+		}
+	}
+}
+
+func TestRewriteHost(t *testing.T) {
+	bindings := map[string]string{"tenant": "acme", "region": "eu-west-1", "path": "a/b"}
+
+	tt := []struct {
+		hostRewrite string
+		expected    string
+		err         string
+	}{
+		{hostRewrite: "{tenant}.internal.svc", expected: "acme.internal.svc"},
+		{hostRewrite: "{region}-{tenant}.svc:8080", expected: "eu-west-1-acme.svc:8080"},
+		{hostRewrite: "static.svc", expected: "static.svc"},
+		{hostRewrite: "{path}.svc", err: "Value of variable path is not valid in a host: a/b"},
+		{hostRewrite: "{other}.svc", err: "Variable other in host rewrite is not bound: {other}.svc"},
+		{hostRewrite: "{tenant", err: "Unmatched { not allowed in host: {tenant"},
+	}
+	for _, tc := range tt {
+		host, err := RewriteHost(tc.hostRewrite, bindings)
+		if len(tc.err) == 0 {
+			assert.NilError(t, err, tc.hostRewrite)
+			assert.Equal(t, host, tc.expected)
+		} else {
+			assert.Error(t, err, tc.err, tc.hostRewrite)
+		}
+	}
+
+	_, err := RewriteHost("{tenant}.svc", map[string]string{"tenant": ""})
+	assert.Error(t, err, "Value of variable tenant is not valid in a host: ")
+}
@@ -0,0 +1,116 @@
+package path_template
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// reHTTPRuleFieldPath matches the field path of an httprule variable - name or message.name
+var reHTTPRuleFieldPath = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*(\.[a-zA-Z_][a-zA-Z0-9_]*)*$`)
+
+// ToHTTPRule converts a path template into a google.api.http template, as compiled into
+// httprule patterns by grpc-gateway, so services can reuse envoy validated routes in process.
+// A suffix starting with a colon becomes the verb: /v1/{name}:publish stays /v1/{name}:publish.
+// Other suffixes, text globs followed by more segments and trailing slashes have no equivalent.
+// Note that grpc-gateway matches /v1 with /v1/**, envoy does not.
+func ToHTTPRule(template string) (string, error) {
+	parsed, err := parseValid(template, nil)
+	if err != nil {
+		return "", err
+	}
+	if strings.HasSuffix(template, "/") && len(parsed.Segments) > 0 {
+		return "", fmt.Errorf("Trailing slash has no httprule equivalent: %s", excerpt(template))
+	}
+
+	segments := make([]string, 0, len(parsed.Segments))
+	verb := ""
+	for i, node := range parsed.Segments {
+		text := node.Text
+		if len(node.Suffix) > 0 {
+			if !strings.HasPrefix(node.Suffix, ":") || strings.Count(node.Suffix, ":") > 1 || len(node.Suffix) == 1 {
+				return "", fmt.Errorf("Suffix has no httprule equivalent, only a :verb suffix does: %s", excerpt(node.Suffix))
+			}
+			verb = node.Suffix
+			text = strings.TrimSuffix(text, node.Suffix)
+		}
+		if hasTextGlob(node) && (i != len(parsed.Segments)-1 || !endsWithTextGlob(node)) {
+			return "", fmt.Errorf("Text glob (**) must be the final httprule segment: %s", excerpt(template))
+		}
+		segments = append(segments, text)
+	}
+	return "/" + strings.Join(segments, "/") + verb, nil
+}
+
+// hasTextGlob reports whether a valid node contains a text glob, alone or in a variable pattern
+func hasTextGlob(node Node) bool {
+	switch node.Kind {
+	case NodeTextGlob:
+		return true
+	case NodeVariable:
+		return slices.Contains(strings.Split(node.Pattern, "/"), pathGlob)
+	default:
+		return false
+	}
+}
+
+// endsWithTextGlob reports whether the only text glob of a valid node is its final token
+func endsWithTextGlob(node Node) bool {
+	if node.Kind == NodeTextGlob {
+		return true
+	}
+	tokens := strings.Split(node.Pattern, "/")
+	return slices.Index(tokens, pathGlob) == len(tokens)-1
+}
+
+// FromHTTPRule converts a google.api.http template into a path template.
+// The verb becomes a suffix or is kept in the final literal, both matching the same paths.
+// Variables must have plain field names, envoy variable names can't contain dots.
+func FromHTTPRule(rule string) (string, error) {
+	if !strings.HasPrefix(rule, "/") {
+		return "", fmt.Errorf("httprule template must start with a /: %s", excerpt(rule))
+	}
+
+	// the verb is what follows the last colon outside of variables
+	path, verb := rule, ""
+	depth := 0
+	for i := 0; i < len(rule); i++ {
+		switch rule[i] {
+		case '{':
+			depth++
+		case '}':
+			depth--
+		case ':':
+			if depth == 0 {
+				path, verb = rule[:i], rule[i:]
+			}
+		}
+	}
+	if len(verb) == 1 {
+		return "", fmt.Errorf("Invalid httprule verb: %s", excerpt(rule))
+	}
+
+	spans, diagnostics := scanPathTemplate(path, 1)
+	if len(diagnostics) > 0 {
+		return "", fmt.Errorf("Invalid httprule template: %s", diagnostics[0].Message)
+	}
+	for _, span := range spans {
+		if !strings.HasPrefix(span.text, "{") {
+			continue
+		}
+		name, _, _ := strings.Cut(strings.Trim(span.text, "{}"), "=")
+		if !reHTTPRuleFieldPath.MatchString(name) {
+			return "", fmt.Errorf("Invalid httprule field path: %s", excerpt(name))
+		}
+		if strings.Contains(name, ".") {
+			return "", fmt.Errorf("Field path cannot be a variable name: %s", excerpt(name))
+		}
+	}
+
+	template := path + verb
+	if _, err := ValidatePathTemplate(template); err != nil {
+		return "", err
+	}
+	return template, nil
+}
@@ -0,0 +1,71 @@
+package path_template
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestToHTTPRule(t *testing.T) {
+	tt := []struct {
+		template string
+		rule     string
+		err      string
+	}{
+		{template: "/v1/{name=messages/*}", rule: "/v1/{name=messages/*}"},
+		{template: "/v1/{name}:publish", rule: "/v1/{name}:publish"},
+		{template: "/v1/*/files/**", rule: "/v1/*/files/**"},
+		{template: "/v1/{path=**}:get", rule: "/v1/{path=**}:get"},
+		{template: "/", rule: "/"},
+		{template: "/v1/{name}.json", err: "Suffix has no httprule equivalent, only a :verb suffix does: .json"},
+		{template: "/v1/**/info", err: "Text glob (**) must be the final httprule segment: /v1/**/info"},
+		{template: "/v1/{path=a/**}/info", err: "Text glob (**) must be the final httprule segment: /v1/{path=a/**}/info"},
+		{template: "/{a=**/b}", err: "Text glob (**) must be the final httprule segment: /{a=**/b}"},
+		{template: "/x/{a=c/**/d}", err: "Text glob (**) must be the final httprule segment: /x/{a=c/**/d}"},
+		{template: "/v1/", err: "Trailing slash has no httprule equivalent: /v1/"},
+		{template: "/{a}/{a}", err: "Variable name is duplicated: a"},
+	}
+	for _, tc := range tt {
+		rule, err := ToHTTPRule(tc.template)
+		if len(tc.err) == 0 {
+			assert.NilError(t, err, tc.template)
+			assert.Equal(t, rule, tc.rule)
+		} else {
+			assert.Error(t, err, tc.err, tc.template)
+		}
+	}
+}
+
+func TestFromHTTPRule(t *testing.T) {
+	tt := []struct {
+		rule     string
+		template string
+		err      string
+	}{
+		{rule: "/v1/{name=messages/*}", template: "/v1/{name=messages/*}"},
+		{rule: "/v1/{name}:publish", template: "/v1/{name}:publish"},
+		{rule: "/v1/messages:list", template: "/v1/messages:list"},
+		{rule: "/v1/{name=shelves/*/books/*}:read", template: "/v1/{name=shelves/*/books/*}:read"},
+		{rule: "/v1/**", template: "/v1/**"},
+		{rule: "v1/messages", err: "httprule template must start with a /: v1/messages"},
+		{rule: "/v1/messages:", err: "Invalid httprule verb: /v1/messages:"},
+		{rule: "/v1/{message.name}", err: "Field path cannot be a variable name: message.name"},
+		{rule: "/v1/{1name}", err: "Invalid httprule field path: 1name"},
+		{rule: "/v1//messages", err: "Invalid httprule template: Empty segment not allowed in path template: v1//messages"},
+		{rule: "/{a}/{b}/{c}/{d}/{e}/{f}", err: "Cannot have more than 5 variables: /{a}/{b}/{c}/{d}/{e}/{f}"},
+	}
+	for _, tc := range tt {
+		template, err := FromHTTPRule(tc.rule)
+		if len(tc.err) == 0 {
+			assert.NilError(t, err, tc.rule)
+			assert.Equal(t, template, tc.template)
+
+			// round trip
+			rule, err := ToHTTPRule(template)
+			assert.NilError(t, err, template)
+			assert.Equal(t, rule, tc.rule)
+		} else {
+			assert.Error(t, err, tc.err, tc.rule)
+		}
+	}
+}
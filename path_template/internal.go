@@ -0,0 +1,60 @@
+package path_template
+
+import (
+	"errors"
+	"fmt"
+	"sync/atomic"
+)
+
+// ErrInternal is wrapped by the errors reporting a broken invariant of this package,
+// which is a bug rather than a problem of the template
+var ErrInternal = errors.New("Internal error")
+
+// Invariant identifiers of InternalError
+const (
+	// InvariantLenientParseValid is broken when the lenient parser reports diagnostics for a valid template
+	InvariantLenientParseValid = "lenient-parse-valid"
+	// InvariantLenientParseVariables is broken when the lenient parser and the validator disagree on variables
+	InvariantLenientParseVariables = "lenient-parse-variables"
+	// InvariantTemplateRegexp is broken when the regular expression of a valid template doesn't compile
+	InvariantTemplateRegexp = "template-regexp"
+)
+
+// InternalError is a broken invariant, with enough context for a bug report
+type InternalError struct {
+	// Template is the template being processed
+	Template string
+	// Invariant identifies the broken invariant
+	Invariant string
+	// Detail describes how the invariant is broken
+	Detail string
+}
+
+func (e *InternalError) Error() string {
+	return fmt.Sprintf("%s, invariant %s broken (%s), please report it: %s", ErrInternal, e.Invariant, e.Detail, excerpt(e.Template))
+}
+
+func (e *InternalError) Unwrap() error {
+	return ErrInternal
+}
+
+var internalErrorHook atomic.Pointer[func(*InternalError)]
+
+// OnInternalError registers hook to be called with every internal error, so broken invariants
+// can be reported to the embedder's telemetry. A nil hook unregisters the current one.
+func OnInternalError(hook func(*InternalError)) {
+	if hook == nil {
+		internalErrorHook.Store(nil)
+		return
+	}
+	internalErrorHook.Store(&hook)
+}
+
+// internalError reports a broken invariant and returns it as an error
+func internalError(template, invariant, detail string) error {
+	err := &InternalError{Template: template, Invariant: invariant, Detail: detail}
+	if hook := internalErrorHook.Load(); hook != nil {
+		(*hook)(err)
+	}
+	return err
+}
@@ -0,0 +1,40 @@
+package path_template
+
+import (
+	"errors"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestInternalError(t *testing.T) {
+	reported := []*InternalError{}
+	OnInternalError(func(err *InternalError) {
+		reported = append(reported, err)
+	})
+	defer OnInternalError(nil)
+
+	err := internalError("/a", InvariantLenientParseValid, "Some diagnostic")
+	assert.Assert(t, errors.Is(err, ErrInternal))
+	assert.Error(t, err, "Internal error, invariant lenient-parse-valid broken (Some diagnostic), please report it: /a")
+
+	var internal *InternalError
+	assert.Assert(t, errors.As(err, &internal))
+	assert.DeepEqual(t, reported, []*InternalError{internal})
+
+	OnInternalError(nil)
+	_ = internalError("/b", InvariantLenientParseVariables, "")
+	assert.Equal(t, len(reported), 1)
+}
+
+func TestParseValidKeepsInvariants(t *testing.T) {
+	OnInternalError(func(err *InternalError) {
+		t.Errorf("Unexpected internal error: %s", err)
+	})
+	defer OnInternalError(nil)
+
+	for _, path := range []string{"/", "/a/", "/{a}/{b=c/**}.m3u8", "/*/**"} {
+		_, err := parseValid(path, []Option{WithSuffixVariable()})
+		assert.NilError(t, err)
+	}
+}
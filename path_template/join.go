@@ -0,0 +1,40 @@
+package path_template
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SafeJoin joins path template parts into a single validated path template.
+// A slash is inserted between parts unless one of them already provides it, so a part
+// can never glue onto the previous one and create a prefixed operator - api + {tenant}
+// is joined as /api/{tenant}, never as /api{tenant}.
+// Empty parts and parts that would produce duplicate slashes are rejected, and the result
+// is validated as a whole. Example: SafeJoin("/tenants", tenant, "{path=**}")
+func SafeJoin(parts ...string) (string, error) {
+	var sb strings.Builder
+	for i, part := range parts {
+		if len(part) == 0 {
+			return "", fmt.Errorf("Empty part not allowed in path template join: part %d", i)
+		}
+		joined := sb.String()
+		endsWithSlash := strings.HasSuffix(joined, "/")
+		startsWithSlash := strings.HasPrefix(part, "/")
+		if endsWithSlash && startsWithSlash {
+			return "", fmt.Errorf("Duplicate slashes not allowed in path template join: %s + %s", excerpt(joined), excerpt(part))
+		}
+		if !endsWithSlash && !startsWithSlash {
+			sb.WriteByte('/')
+		}
+		sb.WriteString(part)
+	}
+
+	path := sb.String()
+	if len(path) == 0 {
+		return "", fmt.Errorf("Nothing to join in path template join")
+	}
+	if _, err := ValidatePathTemplate(path); err != nil {
+		return "", err
+	}
+	return path, nil
+}
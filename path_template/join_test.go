@@ -0,0 +1,76 @@
+package path_template
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestSafeJoinSuccess(t *testing.T) {
+	tt := []struct {
+		parts []string
+		path  string
+	}{
+		{
+			parts: []string{"/"},
+			path:  "/",
+		},
+		{
+			parts: []string{"api"},
+			path:  "/api",
+		},
+		{
+			parts: []string{"/api", "{tenant}", "v1/**"},
+			path:  "/api/{tenant}/v1/**",
+		},
+		{
+			parts: []string{"/api/", "{tenant}/", "{path=**}.m3u8"},
+			path:  "/api/{tenant}/{path=**}.m3u8",
+		},
+		{
+			parts: []string{"/media", "/{id=*}", "/*"},
+			path:  "/media/{id=*}/*",
+		},
+	}
+	for _, tc := range tt {
+		path, err := SafeJoin(tc.parts...)
+		assert.NilError(t, err)
+		assert.Equal(t, path, tc.path)
+	}
+}
+
+func TestSafeJoinFailure(t *testing.T) {
+	tt := []struct {
+		parts []string
+		err   string
+	}{
+		{
+			parts: nil,
+			err:   "Nothing to join in path template join",
+		},
+		{
+			parts: []string{"/api", ""},
+			err:   "Empty part not allowed in path template join: part 1",
+		},
+		{
+			parts: []string{"/api/", "/v1"},
+			err:   "Duplicate slashes not allowed in path template join: /api/ + /v1",
+		},
+		{
+			parts: []string{"/api", "v{version}"},
+			err:   "Prefixes not allowed before operators: v{version}",
+		},
+		{
+			parts: []string{"/api", "**", "{tenant}"},
+			err:   "Cannot have variable after text glob (**): {tenant}",
+		},
+		{
+			parts: []string{"/api", "a//b"},
+			err:   "Empty segment not allowed in path template: api/a//b",
+		},
+	}
+	for _, tc := range tt {
+		_, err := SafeJoin(tc.parts...)
+		assert.Error(t, err, tc.err)
+	}
+}
@@ -0,0 +1,65 @@
+package path_template
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// LabelSafetyLevel rates whether values matched by a template are safe to use as metric labels
+type LabelSafetyLevel int
+
+const (
+	// LabelSafe templates only capture values from a bounded set
+	LabelSafe LabelSafetyLevel = iota
+	// LabelUnsafe templates capture arbitrary values, using them as labels may explode cardinality
+	LabelUnsafe
+)
+
+func (l LabelSafetyLevel) String() string {
+	switch l {
+	case LabelSafe:
+		return "safe"
+	case LabelUnsafe:
+		return "unsafe"
+	default:
+		return fmt.Sprintf("LabelSafetyLevel(%d)", int(l))
+	}
+}
+
+// Rating is the label safety rating of a template along with its rationale
+type Rating struct {
+	Level LabelSafetyLevel
+	// Reasons explain an unsafe rating, one per offending segment
+	Reasons []string
+}
+
+// LabelSafety rates whether the values a path template matches are safe to use as metric labels.
+// Variables are only safe when their pattern is made of literals, ie {version=v1}.
+// Variables capturing * are unbounded in value and text globs (**) are also unbounded in depth.
+func LabelSafety(path string, opts ...Option) (Rating, error) {
+	parsed, err := parseValid(path, opts)
+	if err != nil {
+		return Rating{}, err
+	}
+
+	rating := Rating{Level: LabelSafe, Reasons: []string{}}
+	for _, node := range parsed.Segments {
+		switch node.Kind {
+		case NodeTextGlob:
+			rating.Reasons = append(rating.Reasons, fmt.Sprintf("Text glob (**) matches an unbounded number of segments: %s", node.Text))
+		case NodeVariable:
+			patternSegments := strings.Split(node.Pattern, "/")
+			switch {
+			case slices.Contains(patternSegments, pathGlob):
+				rating.Reasons = append(rating.Reasons, fmt.Sprintf("Variable %s captures an unbounded number of segments: %s", node.Name, node.Text))
+			case len(node.Pattern) == 0 || slices.Contains(patternSegments, textGlob):
+				rating.Reasons = append(rating.Reasons, fmt.Sprintf("Variable %s captures unconstrained values: %s", node.Name, node.Text))
+			}
+		}
+	}
+	if len(rating.Reasons) > 0 {
+		rating.Level = LabelUnsafe
+	}
+	return rating, nil
+}
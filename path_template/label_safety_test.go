@@ -0,0 +1,62 @@
+package path_template
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestLabelSafety(t *testing.T) {
+	tt := []struct {
+		path    string
+		level   LabelSafetyLevel
+		reasons []string
+	}{
+		{
+			path:    "/api/v1/users",
+			level:   LabelSafe,
+			reasons: []string{},
+		},
+		{
+			path:    "/api/*/{version=v1}/*.json",
+			level:   LabelSafe,
+			reasons: []string{},
+		},
+		{
+			path:  "/api/{tenant}/{id=*}",
+			level: LabelUnsafe,
+			reasons: []string{
+				"Variable tenant captures unconstrained values: {tenant}",
+				"Variable id captures unconstrained values: {id=*}",
+			},
+		},
+		{
+			path:  "/media/{path=videos/**}.m3u8",
+			level: LabelUnsafe,
+			reasons: []string{
+				"Variable path captures an unbounded number of segments: {path=videos/**}.m3u8",
+			},
+		},
+		{
+			path:  "/static/**",
+			level: LabelUnsafe,
+			reasons: []string{
+				"Text glob (**) matches an unbounded number of segments: **",
+			},
+		},
+	}
+	for _, tc := range tt {
+		rating, err := LabelSafety(tc.path)
+		assert.NilError(t, err)
+		assert.Equal(t, rating.Level, tc.level, tc.path)
+		assert.DeepEqual(t, rating.Reasons, tc.reasons)
+	}
+
+	_, err := LabelSafety("/api/v*")
+	assert.Error(t, err, "Prefixes not allowed before operators: v*")
+}
+
+func TestLabelSafetyLevelString(t *testing.T) {
+	assert.Equal(t, LabelSafe.String(), "safe")
+	assert.Equal(t, LabelUnsafe.String(), "unsafe")
+}
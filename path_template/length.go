@@ -0,0 +1,51 @@
+package path_template
+
+import (
+	"fmt"
+	"strings"
+)
+
+// maxExcerptLength is the length of the excerpts of user input quoted in errors
+const maxExcerptLength = 64
+
+// WithMaxLength rejects path templates and path template rewrites longer than n bytes before
+// parsing them, bounding the work spent on machine generated input. n <= 0 means no cap.
+func WithMaxLength(n int) Option {
+	return func(o *options) {
+		o.maxLength = n
+	}
+}
+
+// WithMaxSegments rejects path templates with more than n segments. n <= 0 means no cap.
+func WithMaxSegments(n int) Option {
+	return func(o *options) {
+		o.maxSegments = n
+	}
+}
+
+// checkLength checks the length cap, kind names the checked text in the error
+func (o *options) checkLength(kind, text string) error {
+	if o.maxLength > 0 && len(text) > o.maxLength {
+		return fmt.Errorf("%s exceeds %d characters: %s", kind, o.maxLength, excerpt(text))
+	}
+	return nil
+}
+
+// checkSegments checks the segment cap of a path template
+func (o *options) checkSegments(path string, segments int) error {
+	if o.maxSegments > 0 && segments > o.maxSegments {
+		return fmt.Errorf("PathTemplate exceeds %d segments: %s", o.maxSegments, excerpt(path))
+	}
+	return nil
+}
+
+// excerpt truncates long text quoted in errors, so huge inputs are not echoed in full
+func excerpt(text string) string {
+	if len(text) <= maxExcerptLength {
+		return text
+	}
+	var sb strings.Builder
+	sb.WriteString(text[:maxExcerptLength])
+	fmt.Fprintf(&sb, "... (%d more characters)", len(text)-maxExcerptLength)
+	return sb.String()
+}
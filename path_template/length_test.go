@@ -0,0 +1,69 @@
+package path_template
+
+import (
+	"strings"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestWithMaxLength(t *testing.T) {
+	long := "/" + strings.Repeat("a", 99)
+
+	_, err := ValidatePathTemplate(long)
+	assert.NilError(t, err)
+	_, err = ValidatePathTemplate(long, WithMaxLength(100))
+	assert.NilError(t, err)
+
+	_, err = ValidatePathTemplate(long+"/{b}/{b}", WithMaxLength(100))
+	assert.Error(t, err, "PathTemplate exceeds 100 characters: /"+strings.Repeat("a", 63)+"... (44 more characters)")
+
+	_, err = PathTemplateRewriteVariables(long+"/", WithMaxLength(100))
+	assert.Error(t, err, "Path template rewrite exceeds 100 characters: /"+strings.Repeat("a", 63)+"... (37 more characters)")
+
+	_, diagnostics := ParseLenient(long+"/", WithMaxLength(100))
+	assert.DeepEqual(t, diagnostics, []Diagnostic{{
+		Range:   Range{Start: 100, End: 101},
+		Message: "PathTemplate exceeds 100 characters: /" + strings.Repeat("a", 63) + "... (37 more characters)",
+	}})
+}
+
+func TestWithMaxSegments(t *testing.T) {
+	_, err := ValidatePathTemplate("/a/b/c", WithMaxSegments(3))
+	assert.NilError(t, err)
+	_, err = ValidatePathTemplate("/a/{b=c/d}/e/f", WithMaxSegments(3))
+	assert.Error(t, err, "PathTemplate exceeds 3 segments: /a/{b=c/d}/e/f")
+
+	parsed, diagnostics := ParseLenient("/a/b/c/d", WithMaxSegments(3))
+	assert.Equal(t, len(parsed.Segments), 3)
+	assert.DeepEqual(t, diagnostics, []Diagnostic{{
+		Range:   Range{Start: 7, End: 8},
+		Message: "PathTemplate exceeds 3 segments: /a/b/c/d",
+	}})
+}
+
+func TestExcerpt(t *testing.T) {
+	assert.Equal(t, excerpt("/a"), "/a")
+	assert.Equal(t, excerpt(strings.Repeat("a", 64)), strings.Repeat("a", 64))
+	assert.Equal(t, excerpt(strings.Repeat("a", 10000)), strings.Repeat("a", 64)+"... (9936 more characters)")
+}
+
+// TestErrorsQuoteExcerpts checks that errors quote long user input as excerpts
+func TestErrorsQuoteExcerpts(t *testing.T) {
+	long := strings.Repeat("a", 1000)
+
+	_, err := ValidatePathTemplate("/" + long + "//x")
+	assert.Error(t, err, "Empty segment not allowed in path template: "+strings.Repeat("a", 64)+"... (939 more characters)")
+	_, err = ValidatePathTemplate("/{" + long + "}")
+	assert.Error(t, err, "Variable name exceeds 16 characters: "+strings.Repeat("a", 64)+"... (936 more characters)")
+	err = ValidatePathTemplateRewrite("/{b}/"+long, []string{"a"})
+	assert.Error(t, err, "Variable b in path template rewrite is not present in the path template: /{b}/"+strings.Repeat("a", 59)+"... (941 more characters)")
+	_, err = ValidateURLTemplate("https://" + long + " /a")
+	assert.ErrorContains(t, err, "... (")
+	assert.Assert(t, !strings.Contains(err.Error(), long), err.Error())
+	_, diagnostics := ParseLenient("/a/" + long + " ")
+	assert.Assert(t, len(diagnostics) > 0)
+	for _, d := range diagnostics {
+		assert.Assert(t, !strings.Contains(d.Message, long), d.Message)
+	}
+}
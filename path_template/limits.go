@@ -0,0 +1,41 @@
+package path_template
+
+// ValidationLimits are the bounds a path template is validated against
+type ValidationLimits struct {
+	// MaxVariables is the maximum number of variables in a path template
+	MaxVariables int
+	// MinVariableNameLength is the minimum length of a variable name
+	MinVariableNameLength int
+	// MaxVariableNameLength is the maximum length of a variable name
+	MaxVariableNameLength int
+}
+
+func defaultLimits() ValidationLimits {
+	return ValidationLimits{
+		MaxVariables:          defaultEnvoyMaxVariablePerPath,
+		MinVariableNameLength: defaultEnvoyMinNameLength,
+		MaxVariableNameLength: defaultEnvoyMaxNameLength,
+	}
+}
+
+// Limits returns the limits in effect when validating with opts,
+// so callers can display them without hard-coding envoy's values
+func Limits(opts ...Option) ValidationLimits {
+	return newOptions(opts).limits
+}
+
+// WithLimits validates against limits instead of envoy's, for deployments with patched limits.
+// Zero fields keep envoy's value.
+func WithLimits(limits ValidationLimits) Option {
+	return func(o *options) {
+		if limits.MaxVariables > 0 {
+			o.limits.MaxVariables = limits.MaxVariables
+		}
+		if limits.MinVariableNameLength > 0 {
+			o.limits.MinVariableNameLength = limits.MinVariableNameLength
+		}
+		if limits.MaxVariableNameLength > 0 {
+			o.limits.MaxVariableNameLength = limits.MaxVariableNameLength
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package path_template
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestLimits(t *testing.T) {
+	limits := Limits()
+	assert.DeepEqual(t, limits, ValidationLimits{
+		MaxVariables:          5,
+		MinVariableNameLength: 1,
+		MaxVariableNameLength: 16,
+	})
+
+	// the limits reported are the ones enforced
+	var sb strings.Builder
+	for i := range limits.MaxVariables {
+		fmt.Fprintf(&sb, "/{v%d}", i)
+	}
+	_, err := ValidatePathTemplate(sb.String())
+	assert.NilError(t, err)
+
+	_, err = ValidatePathTemplate(sb.String() + "/{extra}")
+	assert.ErrorContains(t, err, "Cannot have more than 5 variables")
+
+	_, err = ValidatePathTemplate("/{" + strings.Repeat("a", limits.MaxVariableNameLength) + "}")
+	assert.NilError(t, err)
+
+	_, err = ValidatePathTemplate("/{" + strings.Repeat("a", limits.MaxVariableNameLength+1) + "}")
+	assert.ErrorContains(t, err, "Variable name exceeds 16 characters")
+}
+
+func TestWithLimits(t *testing.T) {
+	opts := []Option{WithLimits(ValidationLimits{MaxVariables: 1, MinVariableNameLength: 2})}
+	assert.DeepEqual(t, Limits(opts...), ValidationLimits{
+		MaxVariables:          1,
+		MinVariableNameLength: 2,
+		MaxVariableNameLength: 16,
+	})
+
+	_, err := ValidatePathTemplate("/{ab}", opts...)
+	assert.NilError(t, err)
+	_, err = ValidatePathTemplate("/{ab}/{cd}", opts...)
+	assert.ErrorContains(t, err, "Cannot have more than 1 variables")
+	_, err = ValidatePathTemplate("/{a}", opts...)
+	assert.Error(t, err, "Variable name is shorter than 2 characters: a")
+}
@@ -0,0 +1,212 @@
+package path_template
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// MinimizeAction is the kind of transformation applied by Minimize
+type MinimizeAction int
+
+const (
+	// ActionRemoveCovered removes a template matching only paths another template matches
+	ActionRemoveCovered MinimizeAction = iota
+	// ActionMergeSiblings replaces sibling templates by a template with a path glob
+	ActionMergeSiblings
+)
+
+func (a MinimizeAction) String() string {
+	switch a {
+	case ActionRemoveCovered:
+		return "remove-covered"
+	case ActionMergeSiblings:
+		return "merge-siblings"
+	default:
+		return fmt.Sprintf("MinimizeAction(%d)", int(a))
+	}
+}
+
+// Transformation records a change made by Minimize, for human review
+type Transformation struct {
+	Action MinimizeAction
+	// Inputs are the templates removed or merged
+	Inputs []string
+	// Result is the covering template for ActionRemoveCovered or the merged template for ActionMergeSiblings
+	Result string
+}
+
+// MinimizeOptions configures Minimize
+type MinimizeOptions struct {
+	// MergeSiblings merges templates only differing by a literal segment into a single template
+	// with a path glob in its place - /api/users and /api/orders into /api/*.
+	// The merged template matches more paths than the siblings, so it is opt-in and only applied
+	// when the merged template does not overlap any other template.
+	MergeSiblings bool
+}
+
+// Minimize removes the templates fully covered by other templates, ie /api/users when /api/** is present.
+// Among equivalent templates the first one is kept. Templates keep their relative order.
+// Every change is reported as a Transformation. Note that variable names are not taken into account:
+// a covered template may capture variables its covering template doesn't.
+func Minimize(templates []string, opts MinimizeOptions) ([]string, []Transformation, error) {
+	return MinimizeContext(context.Background(), templates, opts)
+}
+
+// MinimizeContext is Minimize profiled with the pprof labels of ctx, see NewOverlapMatrixContext
+func MinimizeContext(ctx context.Context, templates []string, opts MinimizeOptions) (minimized []string, transformations []Transformation, err error) {
+	profileOperation(ctx, "minimize", len(templates), func(context.Context) {
+		minimized, transformations, err = minimize(templates, opts)
+	})
+	return minimized, transformations, err
+}
+
+func minimize(templates []string, opts MinimizeOptions) ([]string, []Transformation, error) {
+	parsed := make([]*ParsedTemplate, 0, len(templates))
+	for _, template := range templates {
+		p, err := parseValid(template, nil)
+		if err != nil {
+			return nil, nil, err
+		}
+		parsed = append(parsed, p)
+	}
+
+	transformations := []Transformation{}
+	removed := make([]bool, len(parsed))
+	for i := range parsed {
+		for j := range parsed {
+			if i == j || removed[j] || !coversTemplate(parsed[j], parsed[i]) {
+				continue
+			}
+			// equivalent templates, keep the first one
+			if j > i && coversTemplate(parsed[i], parsed[j]) {
+				continue
+			}
+			removed[i] = true
+			transformations = append(transformations, Transformation{
+				Action: ActionRemoveCovered,
+				Inputs: []string{templates[i]},
+				Result: templates[j],
+			})
+			break
+		}
+	}
+
+	// merged templates replace their first sibling, the others are dropped
+	replacement := make([]string, len(parsed))
+	if opts.MergeSiblings {
+		for _, group := range siblingGroups(parsed, removed) {
+			// each template is merged at most once
+			if slices.ContainsFunc(group.members, func(i int) bool { return removed[i] || len(replacement[i]) > 0 }) {
+				continue
+			}
+			merged := mergeSiblings(parsed, group)
+			mergedParsed, err := parseValid(merged.Result, nil)
+			if err != nil || overlapsOthers(mergedParsed, parsed, removed, group) {
+				continue
+			}
+			for _, i := range group.members {
+				removed[i] = true
+			}
+			removed[group.members[0]] = false
+			replacement[group.members[0]] = merged.Result
+			transformations = append(transformations, merged)
+		}
+	}
+
+	minimized := []string{}
+	for i, template := range templates {
+		switch {
+		case removed[i]:
+		case len(replacement[i]) > 0:
+			minimized = append(minimized, replacement[i])
+		default:
+			minimized = append(minimized, template)
+		}
+	}
+	return minimized, transformations, nil
+}
+
+// siblingGroup is a set of templates only differing by the literal segment at position
+type siblingGroup struct {
+	position int
+	members  []int
+}
+
+// siblingGroups finds the templates only differing by a single literal segment, in order of first member.
+// A template joins at most one existing group but may be the first member of several groups.
+func siblingGroups(parsed []*ParsedTemplate, removed []bool) []siblingGroup {
+	groups := []siblingGroup{}
+	index := map[string]int{}
+	grouped := make([]bool, len(parsed))
+	for i, p := range parsed {
+		if removed[i] {
+			continue
+		}
+		for position, node := range p.Segments {
+			if node.Kind != NodeLiteral || grouped[i] {
+				continue
+			}
+			key := siblingKey(p, position)
+			g, ok := index[key]
+			if !ok {
+				index[key] = len(groups)
+				groups = append(groups, siblingGroup{position: position, members: []int{i}})
+				continue
+			}
+			groups[g].members = append(groups[g].members, i)
+			grouped[i] = true
+			grouped[groups[g].members[0]] = true
+		}
+	}
+
+	siblings := []siblingGroup{}
+	for _, g := range groups {
+		if len(g.members) > 1 {
+			siblings = append(siblings, g)
+		}
+	}
+	return siblings
+}
+
+// siblingKey identifies the siblings of a template at position - /api/users at 1 -> api/\x00
+func siblingKey(p *ParsedTemplate, position int) string {
+	texts := make([]string, 0, len(p.Segments)+1)
+	for i, node := range p.Segments {
+		if i == position {
+			texts = append(texts, "\x00")
+			continue
+		}
+		texts = append(texts, node.Text)
+	}
+	// /a and /a/ have the same segments but are different templates
+	texts = append(texts, fmt.Sprint(strings.HasSuffix(p.Text, "/")))
+	return strings.Join(texts, "/")
+}
+
+// mergeSiblings replaces the differing literal of the first sibling with a path glob
+func mergeSiblings(parsed []*ParsedTemplate, group siblingGroup) Transformation {
+	first := parsed[group.members[0]]
+	node := first.Segments[group.position]
+	merged := first.Text[:node.Range.Start] + textGlob + first.Text[node.Range.End:]
+
+	inputs := make([]string, 0, len(group.members))
+	for _, i := range group.members {
+		inputs = append(inputs, parsed[i].Text)
+	}
+	return Transformation{Action: ActionMergeSiblings, Inputs: inputs, Result: merged}
+}
+
+// overlapsOthers reports whether merged may match a path of a kept template outside the group
+func overlapsOthers(merged *ParsedTemplate, parsed []*ParsedTemplate, removed []bool, group siblingGroup) bool {
+	for i, p := range parsed {
+		if removed[i] || slices.Contains(group.members, i) {
+			continue
+		}
+		if overlapsTemplate(merged, p) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,95 @@
+package path_template
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestMinimize(t *testing.T) {
+	tt := []struct {
+		templates       []string
+		minimized       []string
+		transformations []Transformation
+	}{
+		{
+			templates:       []string{"/api/users", "/api/orders"},
+			minimized:       []string{"/api/users", "/api/orders"},
+			transformations: []Transformation{},
+		},
+		{
+			templates: []string{"/api/users", "/api/**", "/api/{id}/x", "/static/*"},
+			minimized: []string{"/api/**", "/static/*"},
+			transformations: []Transformation{
+				{Action: ActionRemoveCovered, Inputs: []string{"/api/users"}, Result: "/api/**"},
+				{Action: ActionRemoveCovered, Inputs: []string{"/api/{id}/x"}, Result: "/api/**"},
+			},
+		},
+		{
+			// equivalent templates keep the first one
+			templates: []string{"/users/{id}", "/users/*", "/users/{id=*}"},
+			minimized: []string{"/users/{id}"},
+			transformations: []Transformation{
+				{Action: ActionRemoveCovered, Inputs: []string{"/users/*"}, Result: "/users/{id}"},
+				{Action: ActionRemoveCovered, Inputs: []string{"/users/{id=*}"}, Result: "/users/{id}"},
+			},
+		},
+		{
+			templates: []string{"/media/*.m3u8", "/media/*", "/media/{p=**}.m3u8", "/a/{p=**}.ts"},
+			minimized: []string{"/media/*", "/media/{p=**}.m3u8", "/a/{p=**}.ts"},
+			transformations: []Transformation{
+				{Action: ActionRemoveCovered, Inputs: []string{"/media/*.m3u8"}, Result: "/media/*"},
+			},
+		},
+	}
+	for _, tc := range tt {
+		minimized, transformations, err := Minimize(tc.templates, MinimizeOptions{})
+		assert.NilError(t, err)
+		assert.DeepEqual(t, minimized, tc.minimized)
+		assert.DeepEqual(t, transformations, tc.transformations)
+	}
+}
+
+func TestMinimizeMergeSiblings(t *testing.T) {
+	tt := []struct {
+		templates       []string
+		minimized       []string
+		transformations []Transformation
+	}{
+		{
+			templates: []string{"/api/users/{id}", "/health", "/api/orders/{id}"},
+			minimized: []string{"/api/*/{id}", "/health"},
+			transformations: []Transformation{
+				{Action: ActionMergeSiblings, Inputs: []string{"/api/users/{id}", "/api/orders/{id}"}, Result: "/api/*/{id}"},
+			},
+		},
+		{
+			// the merged template would also match /api/admin/x
+			templates:       []string{"/api/users/x", "/api/orders/x", "/{service}/admin/x"},
+			minimized:       []string{"/api/users/x", "/api/orders/x", "/{service}/admin/x"},
+			transformations: []Transformation{},
+		},
+		{
+			templates:       []string{"/api/users/x", "/api/orders/x", "/api/admin/**"},
+			minimized:       []string{"/api/users/x", "/api/orders/x", "/api/admin/**"},
+			transformations: []Transformation{},
+		},
+		{
+			// trailing slashes are kept apart
+			templates:       []string{"/a/b/", "/a/c"},
+			minimized:       []string{"/a/b/", "/a/c"},
+			transformations: []Transformation{},
+		},
+	}
+	for _, tc := range tt {
+		minimized, transformations, err := Minimize(tc.templates, MinimizeOptions{MergeSiblings: true})
+		assert.NilError(t, err)
+		assert.DeepEqual(t, minimized, tc.minimized)
+		assert.DeepEqual(t, transformations, tc.transformations)
+	}
+}
+
+func TestMinimizeInvalid(t *testing.T) {
+	_, _, err := Minimize([]string{"/a", "/a//b"}, MinimizeOptions{})
+	assert.Error(t, err, "Empty segment not allowed in path template: a//b")
+}
@@ -0,0 +1,138 @@
+package path_template
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrDeadlineExceeded is returned when validation does not finish before the deadline set with WithDeadline
+var ErrDeadlineExceeded = errors.New("Validation deadline exceeded")
+
+// Option configures optional validation behaviour.
+// Without any options, validation follows envoy's uri_template extension.
+type Option func(*options)
+
+type options struct {
+	limits ValidationLimits
+	// strictRFC3986 rejects the relaxed characters envoy accepts in literals
+	strictRFC3986 bool
+	// suffixVariable exposes the suffix of a suffixed operator as SuffixVariableName
+	suffixVariable bool
+	// multipleSuffixes allows suffixed operators which are not final, an extension of the extended dialect
+	multipleSuffixes bool
+	// operatorPrefixes allows literal prefixes before operators, an extension of the extended dialect
+	operatorPrefixes bool
+	// variableReuse allows a variable to appear more than once, an extension of the extended dialect
+	variableReuse bool
+	// optionalSegments allows optional final segments, an extension of the extended dialect
+	optionalSegments bool
+	// collectAllErrors keeps validating after the first error
+	collectAllErrors bool
+	// maxDiagnostics bounds the number of errors collected, 0 means unbounded
+	maxDiagnostics int
+	// deadline bounds the validation time, zero means no deadline
+	deadline time.Time
+	// maxLength caps the length of templates and rewrites, 0 means no cap
+	maxLength int
+	// maxSegments caps the number of segments of templates, 0 means no cap
+	maxSegments int
+	// redactValues replaces the literals quoted in errors with a placeholder
+	redactValues bool
+	// errorPrefix prefixes the messages of errors and diagnostics
+	errorPrefix string
+	// hooks are called around validation
+	hooks Hooks
+	// traceContext is the context of the trace regions of validation, nil means no regions
+	traceContext context.Context
+}
+
+// newOptions applies opts on top of the envoy defaults
+func newOptions(opts []Option) *options {
+	o := &options{
+		limits: defaultLimits(),
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// diagnosticsFull reports whether no more errors should be collected after n errors
+func (o *options) diagnosticsFull(n int) bool {
+	return !o.collectAllErrors || (o.maxDiagnostics > 0 && n >= o.maxDiagnostics)
+}
+
+// deadlineExceeded reports whether validation ran past the deadline
+func (o *options) deadlineExceeded() bool {
+	return !o.deadline.IsZero() && time.Now().After(o.deadline)
+}
+
+// WithCollectAllErrors keeps validating a path template after the first error,
+// so a template with several problems reports all of them at once.
+// Each invalid segment contributes at most one error, the errors are joined with errors.Join.
+func WithCollectAllErrors() Option {
+	return func(o *options) {
+		o.collectAllErrors = true
+	}
+}
+
+// WithMaxDiagnostics stops validation once n errors have been collected, bounding the work
+// spent on pathological inputs when collecting all errors or parsing leniently.
+// n <= 0 means no bound.
+func WithMaxDiagnostics(n int) Option {
+	return func(o *options) {
+		o.maxDiagnostics = n
+	}
+}
+
+// WithDeadline stops validation with ErrDeadlineExceeded once the deadline has passed.
+// The deadline is checked between segments, so interactive tools can cap latency.
+func WithDeadline(deadline time.Time) Option {
+	return func(o *options) {
+		o.deadline = deadline
+	}
+}
+
+// WithErrorPrefix prefixes every error and diagnostic message with prefix, typically the field path
+// of the template in a config holding many resources, so aggregated output is attributable:
+// spec.routes[3].match: Variable name is duplicated: id
+func WithErrorPrefix(prefix string) Option {
+	return func(o *options) {
+		o.errorPrefix = prefix
+	}
+}
+
+// reportError applies redaction and the error prefix to a validation error.
+// Joined errors are prefixed one by one, the sentinel errors stay checkable with errors.Is.
+func (o *options) reportError(err error) error {
+	err = o.redactError(err)
+	if len(o.errorPrefix) == 0 || err == nil {
+		return err
+	}
+	// keeps its type, each component message is prefixed
+	if urlErr, ok := err.(*URLTemplateError); ok {
+		return &URLTemplateError{Components: urlErr.Components, prefix: o.errorPrefix}
+	}
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		errs := []error{}
+		for _, e := range joined.Unwrap() {
+			errs = append(errs, fmt.Errorf("%s: %w", o.errorPrefix, e))
+		}
+		return errors.Join(errs...)
+	}
+	return fmt.Errorf("%s: %w", o.errorPrefix, err)
+}
+
+// reportDiagnostics applies redaction and the error prefix to diagnostics
+func (o *options) reportDiagnostics(diagnostics []Diagnostic) []Diagnostic {
+	diagnostics = o.redactDiagnostics(diagnostics)
+	if len(o.errorPrefix) == 0 {
+		return diagnostics
+	}
+	for i := range diagnostics {
+		diagnostics[i].Message = o.errorPrefix + ": " + diagnostics[i].Message
+	}
+	return diagnostics
+}
@@ -0,0 +1,135 @@
+package path_template
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestCollectAllErrors(t *testing.T) {
+	tt := []struct {
+		path string
+		errs []string
+	}{
+		{
+			path: "/{v1}/{v2}/{2bad}/{v3}/{v4}/{v5}/{v6}/{v7}/**/*",
+			errs: []string{
+				"Variable name must start with a letter and contain only alphanumeric characters and underscores: 2bad",
+				"Cannot have more than 5 variables: /{v1}/{v2}/{2bad}/{v3}/{v4}/{v5}/{v6}/{v7}/**/*",
+				"Cannot have path glob (*) after text glob (**)",
+			},
+		},
+		{
+			path: "/*.m3u8/v*/{a}/{a}",
+			errs: []string{
+				"The suffixed operator must in be the final path component: /*.m3u8/v*/{a}/{a}",
+				"Prefixes not allowed before operators: v*",
+				"Variable name is duplicated: a",
+			},
+		},
+		{
+			path: "/{a=x*/***}/{b=}",
+			errs: []string{
+				"Prefixes or suffixes not allowed with variable pattern operators: x*",
+				"Variable pattern is empty for: b",
+			},
+		},
+		{
+			// structural errors stop validation
+			path: "/{a}/{b",
+			errs: []string{
+				"Unmatched { not allowed in path template: {a}/{b",
+			},
+		},
+	}
+	for _, tc := range tt {
+		_, err := ValidatePathTemplate(tc.path, WithCollectAllErrors())
+		assert.Error(t, err, errors.Join(stringErrors(tc.errs)...).Error())
+
+		// fail-fast validation reports the first error only
+		_, err = ValidatePathTemplate(tc.path)
+		assert.Error(t, err, tc.errs[0])
+	}
+}
+
+func TestCollectAllErrorsValid(t *testing.T) {
+	variables, err := ValidatePathTemplate("/{a}/{b=*}/**.m3u8", WithCollectAllErrors())
+	assert.NilError(t, err)
+	assert.DeepEqual(t, variables, []string{"a", "b"})
+}
+
+func stringErrors(messages []string) []error {
+	errs := make([]error, 0, len(messages))
+	for _, m := range messages {
+		errs = append(errs, errors.New(m))
+	}
+	return errs
+}
+
+func TestMaxDiagnostics(t *testing.T) {
+	path := "/{1a}/{2b}/{3c}/{4d}"
+
+	_, err := ValidatePathTemplate(path, WithCollectAllErrors(), WithMaxDiagnostics(2))
+	assert.Error(t, err, errors.Join(
+		errors.New("Variable name must start with a letter and contain only alphanumeric characters and underscores: 1a"),
+		errors.New("Variable name must start with a letter and contain only alphanumeric characters and underscores: 2b"),
+	).Error())
+
+	// without collecting all errors the bound is irrelevant
+	_, err = ValidatePathTemplate(path, WithMaxDiagnostics(2))
+	assert.Error(t, err, "Variable name must start with a letter and contain only alphanumeric characters and underscores: 1a")
+
+	_, diagnostics := ParseLenient(path, WithMaxDiagnostics(3))
+	assert.Equal(t, len(diagnostics), 3)
+
+	_, diagnostics = ParseLenient("/a//b//c//d", WithMaxDiagnostics(1))
+	assert.Equal(t, len(diagnostics), 1)
+}
+
+func TestDeadline(t *testing.T) {
+	expired := WithDeadline(time.Now().Add(-time.Second))
+
+	_, err := ValidatePathTemplate("/a/{b}", expired)
+	assert.Assert(t, errors.Is(err, ErrDeadlineExceeded))
+	assert.Error(t, err, "Validation deadline exceeded: /a/{b}")
+
+	_, diagnostics := ParseLenient("/a/{b}", expired)
+	assert.DeepEqual(t, diagnostics, []Diagnostic{
+		{Range: Range{Start: 1, End: 2}, Message: "Validation deadline exceeded: /a/{b}"},
+	})
+
+	_, err = ValidatePathTemplate("/a/{b}", WithDeadline(time.Now().Add(time.Hour)))
+	assert.NilError(t, err)
+}
+
+func TestWithErrorPrefix(t *testing.T) {
+	prefix := WithErrorPrefix("spec.routes[3].match")
+
+	_, err := ValidatePathTemplate("/{a}/{a}", prefix)
+	assert.Error(t, err, "spec.routes[3].match: Variable name is duplicated: a")
+
+	_, err = ValidatePathTemplate("/{a}/{a}/b*", prefix, WithCollectAllErrors())
+	assert.Error(t, err, "spec.routes[3].match: Variable name is duplicated: a\n"+
+		"spec.routes[3].match: Prefixes not allowed before operators: b*")
+
+	_, err = ValidatePathTemplate("/secret/{a}/{a}", prefix, WithRedactValues())
+	assert.Error(t, err, "spec.routes[3].match: Variable name is duplicated: a")
+
+	_, err = ValidatePathTemplate("/a", prefix, WithDeadline(time.Now().Add(-time.Second)))
+	assert.Assert(t, errors.Is(err, ErrDeadlineExceeded))
+	assert.Error(t, err, "spec.routes[3].match: Validation deadline exceeded: /a")
+
+	err = ValidatePathTemplateRewrite("/{b}", []string{"a"}, WithErrorPrefix("spec.routes[3].rewrite"))
+	assert.Error(t, err, "spec.routes[3].rewrite: Variable b in path template rewrite is not present in the path template: /{b}")
+
+	_, diagnostics := ParseLenient("a", prefix)
+	assert.DeepEqual(t, diagnostics, []Diagnostic{{
+		Range:   Range{Start: 0, End: 0},
+		Message: "spec.routes[3].match: PathTemplate must start with a /: a",
+	}})
+
+	_, err = ValidatePathTemplate("/a", prefix)
+	assert.NilError(t, err)
+}
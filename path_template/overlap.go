@@ -0,0 +1,172 @@
+package path_template
+
+import (
+	"context"
+	"fmt"
+	"sort"
+)
+
+// Overlap is the relation between the paths matched by two templates
+type Overlap int
+
+const (
+	// OverlapNone templates never match the same path
+	OverlapNone Overlap = iota
+	// OverlapPartial templates match some common paths
+	OverlapPartial
+	// OverlapIdentical templates match exactly the same paths
+	OverlapIdentical
+	// OverlapCovers means the first template matches every path the second one matches
+	OverlapCovers
+	// OverlapCoveredBy means the second template matches every path the first one matches
+	OverlapCoveredBy
+)
+
+func (o Overlap) String() string {
+	switch o {
+	case OverlapNone:
+		return "none"
+	case OverlapPartial:
+		return "partial"
+	case OverlapIdentical:
+		return "identical"
+	case OverlapCovers:
+		return "covers"
+	case OverlapCoveredBy:
+		return "covered-by"
+	default:
+		return fmt.Sprintf("Overlap(%d)", int(o))
+	}
+}
+
+// OverlapPair is the overlap between the templates at indexes A and B, with A < B
+type OverlapPair struct {
+	A        int
+	B        int
+	Relation Overlap
+}
+
+// OverlapMatrix holds the pairwise overlap of templates.
+// Only overlapping pairs are stored, ordered by A then B.
+type OverlapMatrix struct {
+	Templates []string
+	Pairs     []OverlapPair
+}
+
+// OverlapSummary counts the overlapping pairs of an OverlapMatrix by relation
+type OverlapSummary struct {
+	Templates int
+	Partial   int
+	Identical int
+	// Covering counts the pairs where one template covers the other
+	Covering int
+	// Ambiguous counts the templates overlapping at least one other template
+	Ambiguous int
+}
+
+// NewOverlapMatrix computes the overlap of every pair of templates, giving a global picture of
+// the ambiguity of a route table. Relations are exact, see Covers and Overlaps.
+func NewOverlapMatrix(templates []string) (*OverlapMatrix, error) {
+	return NewOverlapMatrixContext(context.Background(), templates)
+}
+
+// NewOverlapMatrixContext is NewOverlapMatrix profiled with the pprof labels of ctx: the labels
+// naming the operation are added to them, and the goroutine labels are set back to them on return.
+// Callers labeling their goroutine with pprof.Do should pass the context it gives them.
+func NewOverlapMatrixContext(ctx context.Context, templates []string) (m *OverlapMatrix, err error) {
+	profileOperation(ctx, "overlap-matrix", len(templates), func(context.Context) {
+		m, err = newOverlapMatrix(templates)
+	})
+	return m, err
+}
+
+func newOverlapMatrix(templates []string) (*OverlapMatrix, error) {
+	parsed := make([]*ParsedTemplate, 0, len(templates))
+	for _, template := range templates {
+		p, err := parseValid(template, nil)
+		if err != nil {
+			return nil, err
+		}
+		parsed = append(parsed, p)
+	}
+
+	m := &OverlapMatrix{Templates: templates, Pairs: []OverlapPair{}}
+	for a := range parsed {
+		for b := a + 1; b < len(parsed); b++ {
+			if relation := overlapOf(parsed[a], parsed[b]); relation != OverlapNone {
+				m.Pairs = append(m.Pairs, OverlapPair{A: a, B: b, Relation: relation})
+			}
+		}
+	}
+	return m, nil
+}
+
+func overlapOf(a, b *ParsedTemplate) Overlap {
+	aCoversB := coversTemplate(a, b)
+	bCoversA := coversTemplate(b, a)
+	switch {
+	case aCoversB && bCoversA:
+		return OverlapIdentical
+	case aCoversB:
+		return OverlapCovers
+	case bCoversA:
+		return OverlapCoveredBy
+	case overlapsTemplate(a, b):
+		return OverlapPartial
+	default:
+		return OverlapNone
+	}
+}
+
+// Get returns the overlap between the templates at indexes a and b, a template is identical to itself
+func (m *OverlapMatrix) Get(a, b int) Overlap {
+	if a == b {
+		return OverlapIdentical
+	}
+	swapped := a > b
+	if swapped {
+		a, b = b, a
+	}
+	i := sort.Search(len(m.Pairs), func(i int) bool {
+		p := m.Pairs[i]
+		return p.A > a || (p.A == a && p.B >= b)
+	})
+	if i == len(m.Pairs) || m.Pairs[i].A != a || m.Pairs[i].B != b {
+		return OverlapNone
+	}
+
+	relation := m.Pairs[i].Relation
+	if swapped {
+		switch relation {
+		case OverlapCovers:
+			return OverlapCoveredBy
+		case OverlapCoveredBy:
+			return OverlapCovers
+		}
+	}
+	return relation
+}
+
+// Summary counts the overlapping pairs by relation
+func (m *OverlapMatrix) Summary() OverlapSummary {
+	summary := OverlapSummary{Templates: len(m.Templates)}
+	ambiguous := make([]bool, len(m.Templates))
+	for _, p := range m.Pairs {
+		switch p.Relation {
+		case OverlapPartial:
+			summary.Partial++
+		case OverlapIdentical:
+			summary.Identical++
+		case OverlapCovers, OverlapCoveredBy:
+			summary.Covering++
+		}
+		ambiguous[p.A] = true
+		ambiguous[p.B] = true
+	}
+	for _, a := range ambiguous {
+		if a {
+			summary.Ambiguous++
+		}
+	}
+	return summary
+}
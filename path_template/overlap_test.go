@@ -0,0 +1,54 @@
+package path_template
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestOverlapMatrix(t *testing.T) {
+	m, err := NewOverlapMatrix([]string{
+		"/api/**",
+		"/api/users/{id}",
+		"/api/{resource}/{id}",
+		"/static/*",
+		"/api/users/*",
+		"/health",
+	})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, m.Pairs, []OverlapPair{
+		{A: 0, B: 1, Relation: OverlapCovers},
+		{A: 0, B: 2, Relation: OverlapCovers},
+		{A: 0, B: 4, Relation: OverlapCovers},
+		{A: 1, B: 2, Relation: OverlapCoveredBy},
+		{A: 1, B: 4, Relation: OverlapIdentical},
+		{A: 2, B: 4, Relation: OverlapCovers},
+	})
+
+	assert.Equal(t, m.Get(0, 1), OverlapCovers)
+	assert.Equal(t, m.Get(1, 0), OverlapCoveredBy)
+	assert.Equal(t, m.Get(4, 1), OverlapIdentical)
+	assert.Equal(t, m.Get(3, 5), OverlapNone)
+	assert.Equal(t, m.Get(5, 0), OverlapNone)
+	assert.Equal(t, m.Get(3, 3), OverlapIdentical)
+
+	assert.DeepEqual(t, m.Summary(), OverlapSummary{
+		Templates: 6,
+		Identical: 1,
+		Covering:  5,
+		Ambiguous: 4,
+	})
+}
+
+func TestOverlapMatrixPartial(t *testing.T) {
+	m, err := NewOverlapMatrix([]string{"/**/index.html", "/docs/**"})
+	assert.NilError(t, err)
+	assert.Equal(t, m.Get(0, 1), OverlapPartial)
+	assert.Equal(t, m.Summary().Partial, 1)
+	assert.Equal(t, OverlapPartial.String(), "partial")
+}
+
+func TestOverlapMatrixInvalid(t *testing.T) {
+	_, err := NewOverlapMatrix([]string{"/a", "/b//c"})
+	assert.Error(t, err, "Empty segment not allowed in path template: b//c")
+}
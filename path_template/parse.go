@@ -0,0 +1,332 @@
+package path_template
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Range is a byte range [Start, End) of a path template
+type Range struct {
+	Start int
+	End   int
+}
+
+// Diagnostic is a problem found in a path template, located by its range
+type Diagnostic struct {
+	Range   Range
+	Message string
+}
+
+// NodeKind is the kind of a parsed path template segment
+type NodeKind int
+
+const (
+	// NodeError is a segment that failed validation
+	NodeError NodeKind = iota
+	// NodeLiteral is a literal segment - foo
+	NodeLiteral
+	// NodePathGlob is a path glob - *
+	NodePathGlob
+	// NodeTextGlob is a text glob - **
+	NodeTextGlob
+	// NodeVariable is a variable - {foo} or {foo=bar/*}
+	NodeVariable
+)
+
+func (k NodeKind) String() string {
+	switch k {
+	case NodeError:
+		return "error"
+	case NodeLiteral:
+		return "literal"
+	case NodePathGlob:
+		return "path-glob"
+	case NodeTextGlob:
+		return "text-glob"
+	case NodeVariable:
+		return "variable"
+	default:
+		return fmt.Sprintf("NodeKind(%d)", int(k))
+	}
+}
+
+// Node is a segment of a parsed path template
+type Node struct {
+	Kind NodeKind
+	// Range of the segment in the path template, without the separating slashes
+	Range Range
+	// Text is the raw text of the segment
+	Text string
+	// Name of a variable segment
+	Name string
+	// Pattern of a variable segment, empty for {foo}
+	Pattern string
+	// Suffix of a suffixed operator - .m3u8 for **.m3u8
+	Suffix string
+	// Prefix of a prefixed operator - v for v{major}, see WithOperatorPrefixes
+	Prefix string
+}
+
+// ParsedTemplate is the best-effort parse of a path template
+type ParsedTemplate struct {
+	Text     string
+	Segments []Node
+	// Variables holds the names of the valid variables, in order of appearance
+	Variables []string
+	// Optional is the index of the first optional final segment, 0 when there are none.
+	// See WithOptionalSegments.
+	Optional int
+}
+
+// segmentSpan is a path segment along with its offset in the path template
+type segmentSpan struct {
+	text  string
+	start int
+}
+
+func (s segmentSpan) rng() Range {
+	return Range{Start: s.start, End: s.start + len(s.text)}
+}
+
+// scanPathTemplate splits a path template into segments, starting at offset start.
+// It never stops at errors, the returned diagnostics are in order of appearance.
+// Example: /a/{foo}/b/{bar=*/**} -> [a, {foo}, b, {bar=*/**}]
+func scanPathTemplate(path string, start int) ([]segmentSpan, []Diagnostic) {
+	// the error messages don't include the leading slash
+	body := path[start:]
+
+	segments := []segmentSpan{}
+	diagnostics := []Diagnostic{}
+
+	// used for identifying the start of a new path segment - ie - what comes after /
+	segStart := start
+	insideBrackets := false
+	// position of the opening bracket, for unmatched brackets
+	var bracketStart int
+
+	// parses everything that's between slashes which are not inside variables
+	// {foo=*/x} is a valid path template so we can't just do a simple split
+	for i := start; i < len(path); i++ {
+		switch path[i] {
+		case '/':
+			// we can have patterns like {foo=a/*}
+			if insideBrackets {
+				continue
+			}
+			// this happens for cases like /a//b
+			if segStart == i {
+				diagnostics = append(diagnostics, Diagnostic{
+					Range:   Range{Start: i, End: i + 1},
+					Message: fmt.Sprintf("Empty segment not allowed in path template: %s", excerpt(body)),
+				})
+			} else {
+				segments = append(segments, segmentSpan{text: path[segStart:i], start: segStart})
+			}
+			segStart = i + 1
+		case '{':
+			if insideBrackets {
+				diagnostics = append(diagnostics, Diagnostic{
+					Range:   Range{Start: i, End: i + 1},
+					Message: fmt.Sprintf("Nested brackets not allowed in path template: %s", excerpt(body)),
+				})
+			} else {
+				bracketStart = i
+			}
+			insideBrackets = true
+		case '}':
+			if !insideBrackets {
+				diagnostics = append(diagnostics, Diagnostic{
+					Range:   Range{Start: i, End: i + 1},
+					Message: fmt.Sprintf("Unmatched } not allowed in path template: %s", excerpt(body)),
+				})
+			}
+			insideBrackets = false
+		}
+	}
+	if insideBrackets {
+		diagnostics = append(diagnostics, Diagnostic{
+			Range:   Range{Start: bracketStart, End: len(path)},
+			Message: fmt.Sprintf("Unmatched { not allowed in path template: %s", excerpt(body)),
+		})
+	}
+
+	// treat leftover segment if it exists -i.e /a/{b}/leftoverSegment
+	if segStart != len(path) {
+		segments = append(segments, segmentSpan{text: path[segStart:], start: segStart})
+	}
+
+	return segments, diagnostics
+}
+
+// ParseLenient parses a path template without ever failing, for tooling such as
+// linters and editors that need to analyze broken input.
+// Segments failing validation are kept as NodeError segments and every problem
+// found is reported as a diagnostic.
+func ParseLenient(path string, opts ...Option) (*ParsedTemplate, []Diagnostic) {
+	o := newOptions(opts)
+	parsed, diagnostics := parseLenient(path, o)
+	return parsed, o.reportDiagnostics(diagnostics)
+}
+
+func parseLenient(path string, o *options) (*ParsedTemplate, []Diagnostic) {
+	return parseLenientCached(path, o, nil)
+}
+
+// parseLenientCached is parseLenient reusing the results of segments validated by previous calls
+// with the same cache, if any. Templates with optional segments are parsed again in full.
+func parseLenientCached(path string, o *options, cache *segmentCache) (*ParsedTemplate, []Diagnostic) {
+	if base, optional, ok := o.splitOptionalSegments(path); ok {
+		return parseOptionalSegments(path, base, optional, o)
+	}
+
+	parsed := &ParsedTemplate{Text: path, Segments: []Node{}, Variables: []string{}}
+	diagnostics := []Diagnostic{}
+
+	// too long templates are not scanned at all
+	if err := o.checkLength("PathTemplate", path); err != nil {
+		return parsed, append(diagnostics, Diagnostic{Range: Range{Start: o.maxLength, End: len(path)}, Message: err.Error()})
+	}
+
+	// graphically printable ascii characters, same as rePrintable
+	if i := strings.IndexFunc(path, func(r rune) bool { return r < '!' || r > '~' }); i >= 0 {
+		diagnostics = append(diagnostics, Diagnostic{
+			Range:   Range{Start: i, End: i + 1},
+			Message: fmt.Sprintf("PathTemplate contains non-representable characters: %s", excerpt(path)),
+		})
+	}
+
+	start := 1
+	if !strings.HasPrefix(path, "/") {
+		diagnostics = append(diagnostics, Diagnostic{
+			Range:   Range{Start: 0, End: 0},
+			Message: fmt.Sprintf("PathTemplate must start with a /: %s", excerpt(path)),
+		})
+		start = 0
+	}
+
+	spans, scanDiagnostics := scanPathTemplate(path, start)
+	diagnostics = append(diagnostics, scanDiagnostics...)
+	if err := o.checkSegments(path, len(spans)); err != nil {
+		diagnostics = append(diagnostics, Diagnostic{Range: spans[o.maxSegments].rng(), Message: err.Error()})
+		spans = spans[:o.maxSegments]
+	}
+
+	// all errors are collected, the segments are validated independently
+	collectAll := *o
+	collectAll.collectAllErrors = true
+	v := &pathTemplateValidator{o: &collectAll, path: path, variableNames: []string{}}
+	cache.start(len(spans))
+	for i, span := range spans {
+		if o.deadlineExceeded() {
+			diagnostics = append(diagnostics, Diagnostic{
+				Range:   span.rng(),
+				Message: fmt.Sprintf("%s: %s", ErrDeadlineExceeded, excerpt(path)),
+			})
+			break
+		}
+		if o.maxDiagnostics > 0 && len(diagnostics) >= o.maxDiagnostics {
+			break
+		}
+		if v.foundSuffix && !o.multipleSuffixes {
+			v.foundSuffix = false
+			diagnostics = append(diagnostics, Diagnostic{
+				Range:   span.rng(),
+				Message: fmt.Sprintf("The suffixed operator must in be the final path component: %s", excerpt(path)),
+			})
+		}
+		v.foundSuffix = false
+		node := Node{Range: span.rng(), Text: span.text}
+		if cached, ok := cache.lookup(i, span.text, v); ok {
+			v.restore(cached.after)
+			node = cached.node
+			node.Range = span.rng()
+		} else {
+			before := cache.snapshot(v)
+			if err := v.validateSegment(span.text); err != nil {
+				diagnostics = append(diagnostics, Diagnostic{Range: span.rng(), Message: err.Error()})
+			} else {
+				classifyNode(&node)
+				cache.store(i, before, v, node)
+			}
+		}
+		parsed.Segments = append(parsed.Segments, node)
+	}
+	cache.finish()
+	parsed.Variables = v.variableNames
+
+	if o.maxDiagnostics > 0 && len(diagnostics) > o.maxDiagnostics {
+		diagnostics = diagnostics[:o.maxDiagnostics]
+	}
+	return parsed, diagnostics
+}
+
+// parseOptionalSegments parses a path template ending with optional segments with the brackets
+// removed, locating segments and diagnostics in the original path template
+func parseOptionalSegments(path, base, optional string, o *options) (*ParsedTemplate, []Diagnostic) {
+	parsed, diagnostics := parseLenient(base+optional, o)
+	parsed.Text = path
+	// shifts the ranges past the opening bracket
+	shift := func(r Range) Range {
+		if r.Start >= len(base) {
+			r.Start++
+		}
+		if r.End > len(base) {
+			r.End++
+		}
+		return r
+	}
+	for i := range parsed.Segments {
+		parsed.Segments[i].Range = shift(parsed.Segments[i].Range)
+		if parsed.Optional == 0 && parsed.Segments[i].Range.Start > len(base) {
+			parsed.Optional = i
+		}
+	}
+	for i := range diagnostics {
+		diagnostics[i].Range = shift(diagnostics[i].Range)
+	}
+	return parsed, diagnostics
+}
+
+// parseValid parses a path template, failing with the error ValidatePathTemplate would return
+func parseValid(path string, opts []Option) (*ParsedTemplate, error) {
+	variableNames, err := ValidatePathTemplate(path, opts...)
+	if err != nil {
+		return nil, err
+	}
+	parsed, diagnostics := ParseLenient(path, opts...)
+	if len(diagnostics) > 0 {
+		return nil, internalError(path, InvariantLenientParseValid, diagnostics[0].Message)
+	}
+	// the suffix variable is not a variable of the parsed template
+	variableNames = slices.DeleteFunc(variableNames, func(name string) bool { return name == SuffixVariableName })
+	if !slices.Equal(variableNames, parsed.Variables) {
+		return nil, internalError(path, InvariantLenientParseVariables,
+			fmt.Sprintf("%v != %v", variableNames, parsed.Variables))
+	}
+	return parsed, nil
+}
+
+// classifyNode fills in the kind and details of a valid segment
+func classifyNode(node *Node) {
+	operator := node.Text
+	if rePrefixedSegment.MatchString(operator) {
+		node.Prefix = rePrefixedSegment.FindStringSubmatch(operator)[1]
+		operator = operator[len(node.Prefix):]
+	}
+	if reSuffixedSegment.MatchString(operator) {
+		operator = reSuffixedSegment.FindStringSubmatch(operator)[1]
+		node.Suffix = node.Text[len(node.Prefix)+len(operator):]
+	}
+	switch {
+	case operator == textGlob:
+		node.Kind = NodePathGlob
+	case operator == pathGlob:
+		node.Kind = NodeTextGlob
+	case operator[0] == '{':
+		node.Kind = NodeVariable
+		node.Name, node.Pattern, _ = strings.Cut(operator[1:len(operator)-1], "=")
+	default:
+		node.Kind = NodeLiteral
+	}
+}
@@ -0,0 +1,77 @@
+package path_template
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestParseLenientValid(t *testing.T) {
+	parsed, diagnostics := ParseLenient("/api/*/{id}/{path=a/**}.m3u8")
+	assert.Equal(t, len(diagnostics), 0)
+	assert.DeepEqual(t, parsed, &ParsedTemplate{
+		Text: "/api/*/{id}/{path=a/**}.m3u8",
+		Segments: []Node{
+			{Kind: NodeLiteral, Range: Range{Start: 1, End: 4}, Text: "api"},
+			{Kind: NodePathGlob, Range: Range{Start: 5, End: 6}, Text: "*"},
+			{Kind: NodeVariable, Range: Range{Start: 7, End: 11}, Text: "{id}", Name: "id"},
+			{
+				Kind: NodeVariable, Range: Range{Start: 12, End: 28}, Text: "{path=a/**}.m3u8",
+				Name: "path", Pattern: "a/**", Suffix: ".m3u8",
+			},
+		},
+		Variables: []string{"id", "path"},
+	})
+}
+
+func TestParseLenientInvalid(t *testing.T) {
+	tt := []struct {
+		path        string
+		kinds       []NodeKind
+		diagnostics []Diagnostic
+	}{
+		{
+			path:  "/a//{2b}/**/*",
+			kinds: []NodeKind{NodeLiteral, NodeError, NodeTextGlob, NodeError},
+			diagnostics: []Diagnostic{
+				{Range: Range{Start: 3, End: 4}, Message: "Empty segment not allowed in path template: a//{2b}/**/*"},
+				{Range: Range{Start: 4, End: 8}, Message: "Variable name must start with a letter and contain only alphanumeric characters and underscores: 2b"},
+				{Range: Range{Start: 12, End: 13}, Message: "Cannot have path glob (*) after text glob (**)"},
+			},
+		},
+		{
+			path:  "a/{b",
+			kinds: []NodeKind{NodeLiteral, NodeError},
+			diagnostics: []Diagnostic{
+				{Range: Range{Start: 0, End: 0}, Message: "PathTemplate must start with a /: a/{b"},
+				{Range: Range{Start: 2, End: 4}, Message: "Unmatched { not allowed in path template: a/{b"},
+				{Range: Range{Start: 2, End: 4}, Message: "Invalid segment in path template: {b"},
+			},
+		},
+		{
+			path:  "/*.ts/b\x01",
+			kinds: []NodeKind{NodePathGlob, NodeError},
+			diagnostics: []Diagnostic{
+				{Range: Range{Start: 7, End: 8}, Message: "PathTemplate contains non-representable characters: /*.ts/b\x01"},
+				{Range: Range{Start: 6, End: 8}, Message: "The suffixed operator must in be the final path component: /*.ts/b\x01"},
+				{Range: Range{Start: 6, End: 8}, Message: "Invalid segment in path template: b\x01"},
+			},
+		},
+	}
+	for _, tc := range tt {
+		parsed, diagnostics := ParseLenient(tc.path)
+		assert.DeepEqual(t, diagnostics, tc.diagnostics)
+		kinds := []NodeKind{}
+		for _, node := range parsed.Segments {
+			kinds = append(kinds, node.Kind)
+		}
+		assert.DeepEqual(t, kinds, tc.kinds)
+	}
+}
+
+func TestParseLenientKeepsOptions(t *testing.T) {
+	// the options are shared with the caller, ie a Validator, and must not be changed
+	o := newOptions(nil)
+	parseLenient("/{a}/{a}/b*", o)
+	assert.Assert(t, !o.collectAllErrors)
+}
@@ -1,6 +1,7 @@
 package path_template
 
 import (
+	"errors"
 	"fmt"
 	"regexp"
 	"slices"
@@ -43,6 +44,9 @@ var (
 	// It is used for clearer error messages. Non capturing group - we don't need the operator
 	rePrefixedOperator = regexp.MustCompile(`^[` + validLiteralSymbolsReS + `]+(?:\*|\*\*|{.*}).*$`)
 
+	// rePrefixedSegment is used to match the prefix of a segment: v{major} or img-*.png
+	rePrefixedSegment = regexp.MustCompile(`^([` + validLiteralSymbolsReS + `]+)((?:\*|\*\*|{.*}).*)$`)
+
 	// rePrefixedSuffixedVariablePatternSegment matches path variable path segments
 	// with prefixes and/or suffixes, which are not allowed. It is used for clearer error messages
 	// Non-capturing group - we don't need the operator
@@ -70,296 +74,402 @@ var (
 // length of variable names (at most 16)
 // uniqueness of variable names
 // syntax of variable patterns
-func ValidatePathTemplate(path string) ([]string, error) {
-	if !rePrintable.MatchString(path) {
-		return nil, fmt.Errorf("PathTemplate contains non-representable characters: %s", path)
-	}
+func ValidatePathTemplate(path string, opts ...Option) ([]string, error) {
+	o := newOptions(opts)
+	end := o.startParse(path)
+	variableNames, err := validatePathTemplate(path, o)
+	err = o.reportError(err)
+	end(err)
+	return variableNames, err
+}
 
-	if !strings.HasPrefix(path, "/") {
-		return nil, fmt.Errorf("PathTemplate must start with a /: %s", path)
+func validatePathTemplate(path string, o *options) ([]string, error) {
+	if base, optional, ok := o.splitOptionalSegments(path); ok {
+		// the optional segments are validated along with the segments they complete
+		return validatePathTemplate(base+optional, o)
+	}
+	if err := o.checkLength("PathTemplate", path); err != nil {
+		return nil, err
 	}
 
 	// at this point, valid path segments
-	segments, err := parsePathTemplate(path)
+	segments, err := checkSyntax(path)
 	if err != nil {
 		return nil, err
 	}
+	if err := o.checkSegments(path, len(segments)); err != nil {
+		return nil, err
+	}
+
+	v := &pathTemplateValidator{o: o, path: path, variableNames: []string{}}
+	for _, segment := range segments {
+		if o.deadlineExceeded() {
+			v.errs = append(v.errs, fmt.Errorf("%w: %s", ErrDeadlineExceeded, excerpt(path)))
+			return nil, v.err()
+		}
+		if v.foundSuffix && !o.multipleSuffixes {
+			// only reported once, the following segments are still validated
+			if v.fail(fmt.Errorf("The suffixed operator must in be the final path component: %s", excerpt(path))) {
+				return nil, v.err()
+			}
+		}
+		// tracks whether the final segment is suffixed
+		v.foundSuffix = false
+		if err := v.validateSegment(segment); err != nil && v.fail(err) {
+			return nil, v.err()
+		}
+	}
+	if len(v.errs) > 0 {
+		return nil, v.err()
+	}
+
+	if v.foundSuffix && o.suffixVariable {
+		v.variableNames = append(v.variableNames, SuffixVariableName)
+	}
+
+	return v.variableNames, nil
+}
+
+// CheckSyntax performs only the cheap structural checks of a path template:
+// printable characters, leading slash, balanced brackets and non-empty segments.
+// Useful for on-keystroke feedback, ValidatePathTemplate is still needed for full validation.
+func CheckSyntax(path string) error {
+	_, err := checkSyntax(path)
+	return err
+}
+
+// checkSyntax performs the structural checks and returns the segments of the path template
+func checkSyntax(path string) ([]string, error) {
+	if !rePrintable.MatchString(path) {
+		return nil, fmt.Errorf("PathTemplate contains non-representable characters: %s", excerpt(path))
+	}
+
+	if !strings.HasPrefix(path, "/") {
+		return nil, fmt.Errorf("PathTemplate must start with a /: %s", excerpt(path))
+	}
+
+	return parsePathTemplate(path)
+}
+
+// pathTemplateValidator holds the state carried between the segments of a path template
+type pathTemplateValidator struct {
+	o    *options
+	path string
 
 	// PathTemplates may contain path globs, text globs and variables.
 	// Variable patterns may contain path or text globs. If a wildcard operator is found anywhere
 	// in the PathTemplate string, it must be the last (rightmost) wildcard operator.
-	foundTextGlob := false
+	foundTextGlob bool
 
 	// Suffixes are also allowed for wildcard operators (ie *-suffix or {name}-suffix).
 	// If a suffixed wildcard operator is found, it must be the last (rightmost) wildcard operator in the PathTemplate string.
-	foundSuffix := false
+	foundSuffix bool
 
-	variableNames := []string{}
-	for _, segment := range segments {
-		if foundSuffix {
-			return nil, fmt.Errorf("The suffixed operator must in be the final path component: %s", path)
-		}
-		if reSuffixedSegment.MatchString(segment) {
-			foundSuffix = true
-			// extract the operator, that's what we need to validate - ie *, ** or {...}
-			segment = reSuffixedSegment.FindStringSubmatch(segment)[1]
+	variableNames []string
+	// patterns of the variables by name, for reused variables
+	variablePatterns map[string]string
+
+	// errors found so far, more than one only when collecting all errors
+	errs []error
+}
+
+// fail records err and reports whether validation must stop
+func (v *pathTemplateValidator) fail(err error) bool {
+	v.errs = append(v.errs, err)
+	return v.o.diagnosticsFull(len(v.errs))
+}
+
+// err returns the recorded errors
+func (v *pathTemplateValidator) err() error {
+	if len(v.errs) == 1 {
+		return v.errs[0]
+	}
+	return errors.Join(v.errs...)
+}
+
+// validateSegment validates a single path segment, ie foo, *, {foo=bar/**}-suffix
+func (v *pathTemplateValidator) validateSegment(segment string) error {
+	if v.o.operatorPrefixes && rePrefixedSegment.MatchString(segment) {
+		// drop the prefix, what's left is validated as usual - ie *.png for img-*.png
+		match := rePrefixedSegment.FindStringSubmatch(segment)
+		if v.o.strictRFC3986 {
+			if err := validateStrictLiteral(match[1]); err != nil {
+				return err
+			}
 		}
-		switch {
-		// <..>/*/<..>
-		case segment == textGlob:
-			if foundTextGlob {
-				return nil, fmt.Errorf("Cannot have path glob (*) after text glob (**)")
+		segment = match[2]
+	}
+	if reSuffixedSegment.MatchString(segment) {
+		v.foundSuffix = true
+		// extract the operator, that's what we need to validate - ie *, ** or {...}
+		operator := reSuffixedSegment.FindStringSubmatch(segment)[1]
+		if v.o.strictRFC3986 {
+			if err := validateStrictLiteral(segment[len(operator):]); err != nil {
+				return err
 			}
+		}
+		segment = operator
+	}
+	switch {
+	// <..>/*/<..>
+	case segment == textGlob:
+		if v.foundTextGlob {
+			return fmt.Errorf("Cannot have path glob (*) after text glob (**)")
+		}
 
-		// <..>/**/<..>
-		case segment == pathGlob:
-			if foundTextGlob {
-				return nil, fmt.Errorf("Cannot have text glob (**) after text glob (**)")
-			}
-			foundTextGlob = true
+	// <..>/**/<..>
+	case segment == pathGlob:
+		if v.foundTextGlob {
+			return fmt.Errorf("Cannot have text glob (**) after text glob (**)")
+		}
+		v.foundTextGlob = true
 
-		// <..>/foo/<..>
-		case validLiteralRe.MatchString(segment):
-			continue
+	// <..>/foo/<..>
+	case validLiteralRe.MatchString(segment):
+		if v.o.strictRFC3986 {
+			return validateStrictLiteral(segment)
+		}
 
-		// <..>/{<varSyntax>}/<..>
-		case segment[0] == '{' && segment[len(segment)-1] == '}':
-			if foundTextGlob {
-				return nil, fmt.Errorf("Cannot have variable after text glob (**): %s", segment)
-			}
-			// <..>/{foo=bar}/<..>
-			if strings.ContainsRune(segment, '=') {
-				parts := strings.SplitN(segment, "=", 2)
+	// <..>/{<varSyntax>}/<..>
+	case segment[0] == '{' && segment[len(segment)-1] == '}':
+		if v.foundTextGlob {
+			return fmt.Errorf("Cannot have variable after text glob (**): %s", excerpt(segment))
+		}
+		return v.validateVariable(segment)
 
-				// {foo -> remove opening bracket
-				name := parts[0][1:]
+	// <..>/prefix{...}/<..> or <..>/prefix*/<..> or <..>/prefix**/<..>
+	case rePrefixedOperator.MatchString(segment):
+		return fmt.Errorf("Prefixes not allowed before operators: %s", excerpt(segment))
+	default:
+		return fmt.Errorf("Invalid segment in path template: %s", excerpt(segment))
+	}
+	return nil
+}
 
-				if err := validateVariableName(name, path); err != nil {
-					return nil, err
-				}
-				// two variables with the same name are not allowed - /{foo}/{foo=bar}
-				if slices.Contains(variableNames, name) {
-					return nil, fmt.Errorf("Variable name is duplicated: %s", name)
-				}
-				variableNames = append(variableNames, name)
+// validateVariable validates a variable segment - {foo} or {foo=bar}
+func (v *pathTemplateValidator) validateVariable(segment string) error {
+	// trim the curly braces
+	name, pattern, hasPattern := strings.Cut(segment[1:len(segment)-1], "=")
 
-				if len(variableNames) > defaultEnvoyMaxVariablePerPath {
-					return nil, fmt.Errorf("Cannot have more than %d variables: %s", defaultEnvoyMaxVariablePerPath, path)
-				}
+	if err := validateVariableName(name, v.path, v.o.limits); err != nil {
+		return err
+	}
 
-				// bar} -> remove closing bracket
-				pattern := parts[1][:len(parts[1])-1]
+	switch {
+	// two variables with the same name are not allowed - /{foo}/{foo=bar}
+	case slices.Contains(v.variableNames, name) && !v.o.variableReuse:
+		return fmt.Errorf("Variable name is duplicated: %s", excerpt(name))
 
-				// cannot have {foo=}
-				if len(pattern) == 0 {
-					return nil, fmt.Errorf("Variable pattern is empty for: %s", name)
-				}
-				if pattern[0] == '/' || pattern[len(pattern)-1] == '/' {
-					return nil, fmt.Errorf("Variable pattern cannot start or end with a slash: %s", pattern)
-				}
-				for _, patternSegment := range strings.Split(pattern, "/") {
-					switch {
-					// {foo=<..>/*/<..>}
-					case patternSegment == textGlob:
-						if foundTextGlob {
-							return nil, fmt.Errorf("Cannot have path glob (*) after text glob (**)")
-						}
-
-					// {foo=<..>/**/<..>}
-					case patternSegment == pathGlob:
-						if foundTextGlob {
-							return nil, fmt.Errorf("Cannot have text glob (**) after text glob (**)")
-						}
-						foundTextGlob = true
-
-					// {foo=<..>/bar/<..>}
-					case !strings.ContainsRune(patternSegment, '*'):
-						continue
-
-					// {foo=<..>/prefix-**-suffix/<..>}
-					case rePrefixedSuffixedVariablePatternSegment.MatchString(patternSegment):
-						return nil, fmt.Errorf("Prefixes or suffixes not allowed with variable pattern operators: %s", patternSegment)
-
-					default:
-						return nil, fmt.Errorf("Invalid variable pattern segment: %s", patternSegment)
-					}
-				}
-			} else {
-				// <..>/{foo}/<..>
+	// a reused variable binds a single value, so all its occurrences have the same pattern
+	case slices.Contains(v.variableNames, name):
+		if v.variablePatterns[name] != pattern {
+			return fmt.Errorf("Reused variable must have the same pattern: %s", excerpt(name))
+		}
 
-				// trim the curly braces
-				name := segment[1 : len(segment)-1]
+	default:
+		v.variableNames = append(v.variableNames, name)
+		if v.variablePatterns == nil {
+			v.variablePatterns = map[string]string{}
+		}
+		v.variablePatterns[name] = pattern
 
-				if err := validateVariableName(name, path); err != nil {
-					return nil, err
-				}
+		// reported only for the first variable over the limit
+		if len(v.variableNames) == v.o.limits.MaxVariables+1 {
+			return fmt.Errorf("Cannot have more than %d variables: %s", v.o.limits.MaxVariables, excerpt(v.path))
+		}
+	}
 
-				// two variables with the same name are not allowed - /{foo}/{foo=bar}
-				if slices.Contains(variableNames, name) {
-					return nil, fmt.Errorf("Variable name is duplicated: %s", name)
-				}
+	// <..>/{foo}/<..>
+	if !hasPattern {
+		return nil
+	}
+
+	// <..>/{foo=bar}/<..>
+	// cannot have {foo=}
+	if len(pattern) == 0 {
+		return fmt.Errorf("Variable pattern is empty for: %s", excerpt(name))
+	}
+	if pattern[0] == '/' || pattern[len(pattern)-1] == '/' {
+		return fmt.Errorf("Variable pattern cannot start or end with a slash: %s", excerpt(pattern))
+	}
+	for _, patternSegment := range strings.Split(pattern, "/") {
+		switch {
+		// {foo=<..>/*/<..>}
+		case patternSegment == textGlob:
+			if v.foundTextGlob {
+				return fmt.Errorf("Cannot have path glob (*) after text glob (**)")
+			}
 
-				variableNames = append(variableNames, name)
+		// {foo=<..>/**/<..>}
+		case patternSegment == pathGlob:
+			if v.foundTextGlob {
+				return fmt.Errorf("Cannot have text glob (**) after text glob (**)")
+			}
+			v.foundTextGlob = true
 
-				if len(variableNames) > defaultEnvoyMaxVariablePerPath {
-					return nil, fmt.Errorf("Cannot have more than %d variables: %s", defaultEnvoyMaxVariablePerPath, path)
+		// {foo=<..>/bar/<..>}
+		case !strings.ContainsRune(patternSegment, '*'):
+			if v.o.strictRFC3986 {
+				if err := validateStrictLiteral(patternSegment); err != nil {
+					return err
 				}
 			}
-		// <..>/prefix{...}/<..> or <..>/prefix*/<..> or <..>/prefix**/<..>
-		case rePrefixedOperator.MatchString(segment):
-			return nil, fmt.Errorf("Prefixes not allowed before operators: %s", segment)
+
+		// {foo=<..>/prefix-**-suffix/<..>}
+		case rePrefixedSuffixedVariablePatternSegment.MatchString(patternSegment):
+			return fmt.Errorf("Prefixes or suffixes not allowed with variable pattern operators: %s", excerpt(patternSegment))
+
 		default:
-			return nil, fmt.Errorf("Invalid segment in path template: %s", segment)
+			return fmt.Errorf("Invalid variable pattern segment: %s", excerpt(patternSegment))
 		}
 	}
-
-	return variableNames, nil
+	return nil
 }
 
 // parsePathTemplate splits a path template into segments
 // Example: /a/{foo}/b/{bar=*/**} -> [a, {foo}, b, {bar=*/**}]
 func parsePathTemplate(path string) ([]string, error) {
-	// remove leading slash
-	path = path[1:]
-
-	//split by slashes
-	segments := []string{}
-
-	// used for identifying the start of a new path segment - ie - what comes after /
-	var segStart int
-	var insideBrackets bool
-
-	// parses everything that's between slashes which are not inside variables
-	// {foo=*/x} is a valid path template so we can't just do a simple split
-	for i, c := range path {
-		switch c {
-		case '/':
-			// we can have patterns like {foo=a/*}
-			if insideBrackets {
-				continue
-			}
-			// this happens for cases like /a//b
-			if segStart == i {
-				return nil, fmt.Errorf("Empty segment not allowed in path template: %s", path)
-			}
-			segments = append(segments, path[segStart:i])
-			segStart = i + 1
-		case '{':
-			if insideBrackets {
-				return nil, fmt.Errorf("Nested brackets not allowed in path template: %s", path)
-			}
-			insideBrackets = true
-		case '}':
-			if !insideBrackets {
-				return nil, fmt.Errorf("Unmatched } not allowed in path template: %s", path)
-			}
-			insideBrackets = false
-		default:
-		}
-	}
-	if insideBrackets {
-		return nil, fmt.Errorf("Unmatched { not allowed in path template: %s", path)
+	spans, diagnostics := scanPathTemplate(path, 1)
+	if len(diagnostics) > 0 {
+		return nil, errors.New(diagnostics[0].Message)
 	}
 
-	// treat leftover segment if it exists -i.e /a/{b}/leftoverSegment
-	if segStart != len(path) {
-		segments = append(segments, path[segStart:])
+	segments := make([]string, 0, len(spans))
+	for _, span := range spans {
+		segments = append(segments, span.text)
 	}
-
 	return segments, nil
 }
 
 // Validates the correctness of a path template rewrite.
 // Variable names not present in the match condition are not allowed
-func ValidatePathTemplateRewrite(pathTemplateRewrite string, variableNames []string) error {
-	rewriteVarNames, err := validatePathTemplateRewriteSyntax(pathTemplateRewrite)
+func ValidatePathTemplateRewrite(pathTemplateRewrite string, variableNames []string, opts ...Option) error {
+	o := newOptions(opts)
+	return o.reportError(validatePathTemplateRewrite(pathTemplateRewrite, variableNames, o))
+}
+
+func validatePathTemplateRewrite(pathTemplateRewrite string, variableNames []string, o *options) error {
+	rewriteVarNames, err := validatePathTemplateRewriteSyntax(pathTemplateRewrite, o)
 	if err != nil {
 		return err
 	}
 
-	for varName := range rewriteVarNames {
+	// reported in order of appearance, so the error is deterministic
+	for _, varName := range rewriteVarNames {
 		if !slices.Contains(variableNames, varName) {
-			return fmt.Errorf("Variable %s in path template rewrite is not present in the path template: %s", varName, pathTemplateRewrite)
+			return fmt.Errorf("Variable %s in path template rewrite is not present in the path template: %s", excerpt(varName), excerpt(pathTemplateRewrite))
 		}
 	}
 	return nil
+}
 
+// PathTemplateRewriteVariables validates the syntax of a path template rewrite and returns
+// the names of the variables it references, in order of first appearance
+// Example: /{b}/{a}/{b} -> [b, a]
+func PathTemplateRewriteVariables(pathTemplateRewrite string, opts ...Option) ([]string, error) {
+	o := newOptions(opts)
+	variableNames, err := validatePathTemplateRewriteSyntax(pathTemplateRewrite, o)
+	return variableNames, o.reportError(err)
 }
 
-func validatePathTemplateRewriteSyntax(pathTemplateRewrite string) (map[string]bool, error) {
+// validatePathTemplateRewriteSyntax returns the unique variable names of the rewrite, in order of first appearance
+func validatePathTemplateRewriteSyntax(pathTemplateRewrite string, o *options) ([]string, error) {
+	if err := o.checkLength("Path template rewrite", pathTemplateRewrite); err != nil {
+		return nil, err
+	}
+
 	// the rewrite field must start with a /
 	if !strings.HasPrefix(pathTemplateRewrite, "/") {
-		return nil, fmt.Errorf("Replace path template must start with a /: %s", pathTemplateRewrite)
+		return nil, fmt.Errorf("Replace path template must start with a /: %s", excerpt(pathTemplateRewrite))
 	}
 
 	insideBrackets := false
-	rewriteVarNames := make(map[string]bool)
+	rewriteVarNames := []string{}
 	var startIndex int
 	for i, c := range pathTemplateRewrite {
 		switch c {
 		case '{':
 			if insideBrackets {
-				return nil, fmt.Errorf("Nested brackets in not allowed in path template rewrite: %s", pathTemplateRewrite)
+				return nil, fmt.Errorf("Nested brackets in not allowed in path template rewrite: %s", excerpt(pathTemplateRewrite))
 			}
 			insideBrackets = true
 			if startIndex != i {
 				literal := pathTemplateRewrite[startIndex:i]
 				if !reValidTemplateRewriteLiteral.MatchString(literal) {
-					return nil, fmt.Errorf("Invalid character in path template rewrite: %s", pathTemplateRewrite)
+					return nil, fmt.Errorf("Invalid character in path template rewrite: %s", excerpt(pathTemplateRewrite))
+				}
+				if o.strictRFC3986 {
+					if err := validateStrictLiteral(literal); err != nil {
+						return nil, err
+					}
 				}
 			}
 			startIndex = i + 1
 		case '}':
 			if !insideBrackets {
-				return nil, fmt.Errorf("Unmatched } not allowed in path template rewrite: %s", pathTemplateRewrite)
+				return nil, fmt.Errorf("Unmatched } not allowed in path template rewrite: %s", excerpt(pathTemplateRewrite))
 			}
 			insideBrackets = false
 
 			if startIndex == i {
-				return nil, fmt.Errorf("Empty variable not allowed in path template rewrite: %s", pathTemplateRewrite)
+				return nil, fmt.Errorf("Empty variable not allowed in path template rewrite: %s", excerpt(pathTemplateRewrite))
 			}
 			// take what's between the brackets - that's the name
 			varName := pathTemplateRewrite[startIndex:i]
 
-			if err := validateVariableName(varName, pathTemplateRewrite); err != nil {
-				return nil, err
+			// the implicit suffix variable is exempt from the naming rules
+			isSuffixVariable := o.suffixVariable && varName == SuffixVariableName
+			if !isSuffixVariable {
+				if err := validateVariableName(varName, pathTemplateRewrite, o.limits); err != nil {
+					return nil, err
+				}
 			}
 
 			// we don't care if we have the same variable twice here
 			// /{a}/{b}/{a} is a valid rewrite
-			rewriteVarNames[varName] = true
+			if !slices.Contains(rewriteVarNames, varName) {
+				rewriteVarNames = append(rewriteVarNames, varName)
+			}
 			startIndex = i + 1
 		case '/':
 			if i < len(pathTemplateRewrite)-1 && pathTemplateRewrite[i+1] == '/' {
-				return nil, fmt.Errorf("Empty segment not allowed in path template rewrite: %s", pathTemplateRewrite)
+				return nil, fmt.Errorf("Empty segment not allowed in path template rewrite: %s", excerpt(pathTemplateRewrite))
 			}
 		}
 	}
 	if insideBrackets {
-		return nil, fmt.Errorf("Unmatched { not allowed in path template rewrite: %s", pathTemplateRewrite)
+		return nil, fmt.Errorf("Unmatched { not allowed in path template rewrite: %s", excerpt(pathTemplateRewrite))
 	}
 
 	// treat leftover literal case  /a/{var1}abcd
 	if startIndex != len(pathTemplateRewrite) {
 		literal := pathTemplateRewrite[startIndex:]
 		if !reValidTemplateRewriteLiteral.MatchString(literal) {
-			return nil, fmt.Errorf("Invalid character found in path template rewrite: %s", pathTemplateRewrite)
+			return nil, fmt.Errorf("Invalid character found in path template rewrite: %s", excerpt(pathTemplateRewrite))
+		}
+		if o.strictRFC3986 {
+			if err := validateStrictLiteral(literal); err != nil {
+				return nil, err
+			}
 		}
 	}
 
 	return rewriteVarNames, nil
 }
 
-func validateVariableName(name, fullString string) error {
-	if len(name) < defaultEnvoyMinNameLength {
-		return fmt.Errorf("Variable name cannot be empty: %s", fullString)
+func validateVariableName(name, fullString string, limits ValidationLimits) error {
+	if len(name) == 0 {
+		return fmt.Errorf("Variable name cannot be empty: %s", excerpt(fullString))
+	}
+	if len(name) < limits.MinVariableNameLength {
+		return fmt.Errorf("Variable name is shorter than %d characters: %s", limits.MinVariableNameLength, excerpt(name))
 	}
 
 	if !reVariableName.MatchString(name) {
-		return fmt.Errorf("Variable name must start with a letter and contain only alphanumeric characters and underscores: %s", name)
+		return fmt.Errorf("Variable name must start with a letter and contain only alphanumeric characters and underscores: %s", excerpt(name))
 	}
-	if len(name) > 16 {
-		return fmt.Errorf("Variable name exceeds 16 characters: %s", name)
+	if len(name) > limits.MaxVariableNameLength {
+		return fmt.Errorf("Variable name exceeds %d characters: %s", limits.MaxVariableNameLength, excerpt(name))
 	}
 	return nil
 }
@@ -250,7 +250,7 @@ func TestPathTemplateRewriteSuccess(t *testing.T) {
 		"/abc-def-{var1}/a/{var1}",
 	}
 	for _, rewrite := range validRewrites {
-		_, err := validatePathTemplateRewriteSyntax(rewrite)
+		_, err := validatePathTemplateRewriteSyntax(rewrite, newOptions(nil))
 		assert.NilError(t, err)
 	}
 }
@@ -302,7 +302,7 @@ func TestPathTemplateRewriteFailure(t *testing.T) {
 		},
 	}
 	for _, tc := range tt {
-		_, err := validatePathTemplateRewriteSyntax(tc.rewrite)
+		_, err := validatePathTemplateRewriteSyntax(tc.rewrite, newOptions(nil))
 		assert.Error(t, err, tc.err)
 	}
 }
@@ -372,3 +372,60 @@ func TestPathTemplateMatchRewriteFailure(t *testing.T) {
 		assert.Error(t, err, tc.err)
 	}
 }
+
+func TestPathTemplateRewriteVariables(t *testing.T) {
+	variables, err := PathTemplateRewriteVariables("/{b}/{a}-{b}/x/{c}{a}")
+	assert.NilError(t, err)
+	assert.DeepEqual(t, variables, []string{"b", "a", "c"})
+
+	variables, err = PathTemplateRewriteVariables("/static")
+	assert.NilError(t, err)
+	assert.DeepEqual(t, variables, []string{})
+
+	_, err = PathTemplateRewriteVariables("/{b")
+	assert.Error(t, err, "Unmatched { not allowed in path template rewrite: /{b")
+}
+
+func TestPathTemplateMatchRewriteFailureIsDeterministic(t *testing.T) {
+	// the first missing variable in the rewrite is always reported
+	for range 20 {
+		err := ValidatePathTemplateRewrite("/{a}/{z}/{y}/{x}/{w}", []string{"a"})
+		assert.Error(t, err, "Variable z in path template rewrite is not present in the path template: /{a}/{z}/{y}/{x}/{w}")
+	}
+}
+
+func TestCheckSyntax(t *testing.T) {
+	tt := []struct {
+		path string
+		err  string
+	}{
+		{path: "/a/{b}/**"},
+		// semantic problems are not reported
+		{path: "/**/{b}/{b}/v*"},
+		{path: "a", err: "PathTemplate must start with a /: a"},
+		{path: "/a\x01", err: "PathTemplate contains non-representable characters: /a\x01"},
+		{path: "/a//b", err: "Empty segment not allowed in path template: a//b"},
+		{path: "/{a", err: "Unmatched { not allowed in path template: {a"},
+		{path: "/a}", err: "Unmatched } not allowed in path template: a}"},
+		{path: "/{{a}}", err: "Nested brackets not allowed in path template: {{a}}"},
+	}
+	for _, tc := range tt {
+		err := CheckSyntax(tc.path)
+		if tc.err == "" {
+			assert.NilError(t, err)
+		} else {
+			assert.Error(t, err, tc.err)
+		}
+	}
+}
+
+func FuzzValidatePathTemplate(f *testing.F) {
+	f.Add("/api/{version}/users/{id}")
+	f.Add("/static/{path=**}.js")
+	f.Fuzz(func(t *testing.T, path string) {
+		_, err := ValidatePathTemplate(path)
+		_, diagnostics := ParseLenient(path)
+		// the lenient parser agrees with the validator
+		assert.Equal(t, err == nil, len(diagnostics) == 0, path)
+	})
+}
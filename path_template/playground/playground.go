@@ -0,0 +1,112 @@
+// Package playground provides an embeddable HTTP handler serving an interactive page
+// for validating path templates and path template rewrites.
+package playground
+
+import (
+	_ "embed"
+	"encoding/json"
+	"net/http"
+
+	"github.com/bogdan-deac/path-template/path_template"
+)
+
+//go:embed index.html
+var indexHTML []byte
+
+// maxRequestSize bounds the validation request body
+const maxRequestSize = 64 << 10
+
+// ValidateRequest is the body of a validation request
+type ValidateRequest struct {
+	Template string   `json:"template"`
+	Rewrite  string   `json:"rewrite,omitempty"`
+	Paths    []string `json:"paths,omitempty"`
+}
+
+// Diagnostic is a problem found in the path template
+type Diagnostic struct {
+	Start   int    `json:"start"`
+	End     int    `json:"end"`
+	Message string `json:"message"`
+}
+
+// PathMatch is the result of matching a sample path against the path template
+type PathMatch struct {
+	Path      string            `json:"path"`
+	Match     bool              `json:"match"`
+	Variables map[string]string `json:"variables,omitempty"`
+	Rewritten string            `json:"rewritten,omitempty"`
+}
+
+// ValidateResponse is the result of a validation request
+type ValidateResponse struct {
+	Variables    []string     `json:"variables"`
+	Diagnostics  []Diagnostic `json:"diagnostics"`
+	RewriteError string       `json:"rewrite_error,omitempty"`
+	Matches      []PathMatch  `json:"matches,omitempty"`
+}
+
+// NewHandler returns a handler serving the playground page on / and the validation API on validate.
+// Paths are relative so the handler can be mounted under a prefix with http.StripPrefix.
+func NewHandler(opts ...path_template.Option) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /{$}", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		_, _ = w.Write(indexHTML)
+	})
+	mux.HandleFunc("POST /validate", func(w http.ResponseWriter, r *http.Request) {
+		var req ValidateRequest
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestSize)).Decode(&req); err != nil {
+			http.Error(w, "Invalid validation request: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(Validate(req, opts...))
+	})
+	return mux
+}
+
+// Validate validates the path template and the rewrite of req, matching its sample paths
+// against the template and rewriting them when both are valid
+func Validate(req ValidateRequest, opts ...path_template.Option) ValidateResponse {
+	variableNames, err := path_template.ValidatePathTemplate(req.Template, opts...)
+	if err != nil {
+		parsed, diagnostics := path_template.ParseLenient(req.Template, opts...)
+		resp := ValidateResponse{Variables: parsed.Variables, Diagnostics: make([]Diagnostic, 0, len(diagnostics))}
+		for _, d := range diagnostics {
+			resp.Diagnostics = append(resp.Diagnostics, Diagnostic{Start: d.Range.Start, End: d.Range.End, Message: d.Message})
+		}
+		// options such as WithMaxLength fail validation without a diagnostic
+		if len(diagnostics) == 0 {
+			resp.Diagnostics = append(resp.Diagnostics, Diagnostic{End: len(req.Template), Message: err.Error()})
+		}
+		return resp
+	}
+
+	resp := ValidateResponse{Variables: variableNames, Diagnostics: []Diagnostic{}}
+	// the rewrite can only be checked against a valid template
+	rewrite := len(req.Rewrite) > 0
+	if rewrite {
+		if err := path_template.ValidatePathTemplateRewrite(req.Rewrite, variableNames, opts...); err != nil {
+			resp.RewriteError = err.Error()
+			rewrite = false
+		}
+	}
+	if len(req.Paths) == 0 {
+		return resp
+	}
+	tmpl, err := path_template.Parse(req.Template, opts...)
+	if err != nil {
+		resp.Diagnostics = append(resp.Diagnostics, Diagnostic{End: len(req.Template), Message: err.Error()})
+		return resp
+	}
+	for _, path := range req.Paths {
+		m := PathMatch{Path: path}
+		m.Variables, m.Match = tmpl.Match(path)
+		if m.Match && rewrite {
+			m.Rewritten, _ = tmpl.Rewrite(path, req.Rewrite)
+		}
+		resp.Matches = append(resp.Matches, m)
+	}
+	return resp
+}
@@ -0,0 +1,133 @@
+package playground
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/bogdan-deac/path-template/path_template"
+)
+
+func TestIndex(t *testing.T) {
+	srv := httptest.NewServer(http.StripPrefix("/playground", NewHandler()))
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/playground/")
+	assert.NilError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, resp.StatusCode, http.StatusOK)
+	assert.Equal(t, resp.Header.Get("Content-Type"), "text/html; charset=utf-8")
+}
+
+func TestValidate(t *testing.T) {
+	tt := []struct {
+		body string
+		resp ValidateResponse
+	}{
+		{
+			body: `{"template": "/api/{v}/{path=**}", "rewrite": "/{v}/{path}"}`,
+			resp: ValidateResponse{Variables: []string{"v", "path"}, Diagnostics: []Diagnostic{}},
+		},
+		{
+			body: `{"template": "/api/{v}", "rewrite": "/{v}/{path}"}`,
+			resp: ValidateResponse{
+				Variables:    []string{"v"},
+				Diagnostics:  []Diagnostic{},
+				RewriteError: "Variable path in path template rewrite is not present in the path template: /{v}/{path}",
+			},
+		},
+		{
+			body: `{"template": "/api/{v}/{path=**}", "rewrite": "/{v}/{path}", "paths": ["/api/v1/a/b?x=1", "/other"]}`,
+			resp: ValidateResponse{
+				Variables:   []string{"v", "path"},
+				Diagnostics: []Diagnostic{},
+				Matches: []PathMatch{
+					{Path: "/api/v1/a/b?x=1", Match: true, Variables: map[string]string{"v": "v1", "path": "a/b"}, Rewritten: "/v1/a/b"},
+					{Path: "/other"},
+				},
+			},
+		},
+		{
+			body: `{"template": "/api/{v}", "rewrite": "/{w}", "paths": ["/api/v1"]}`,
+			resp: ValidateResponse{
+				Variables:    []string{"v"},
+				Diagnostics:  []Diagnostic{},
+				RewriteError: "Variable w in path template rewrite is not present in the path template: /{w}",
+				Matches:      []PathMatch{{Path: "/api/v1", Match: true, Variables: map[string]string{"v": "v1"}}},
+			},
+		},
+		{
+			body: `{"template": "/api/v*"}`,
+			resp: ValidateResponse{
+				Variables: []string{},
+				Diagnostics: []Diagnostic{
+					{Start: 5, End: 7, Message: "Prefixes not allowed before operators: v*"},
+				},
+			},
+		},
+	}
+	srv := httptest.NewServer(NewHandler())
+	defer srv.Close()
+
+	for _, tc := range tt {
+		resp, err := http.Post(srv.URL+"/validate", "application/json", strings.NewReader(tc.body))
+		assert.NilError(t, err)
+		assert.Equal(t, resp.StatusCode, http.StatusOK)
+
+		var got ValidateResponse
+		assert.NilError(t, json.NewDecoder(resp.Body).Decode(&got))
+		resp.Body.Close()
+		assert.DeepEqual(t, got, tc.resp)
+	}
+}
+
+func TestValidateSuffixVariable(t *testing.T) {
+	resp := Validate(ValidateRequest{
+		Template: "/videos/{path=**}.m3u8",
+		Rewrite:  "/{path}{__suffix}",
+		Paths:    []string{"/videos/a/b.m3u8"},
+	}, path_template.WithSuffixVariable())
+	assert.DeepEqual(t, resp, ValidateResponse{
+		Variables:   []string{"path", path_template.SuffixVariableName},
+		Diagnostics: []Diagnostic{},
+		Matches: []PathMatch{{
+			Path:      "/videos/a/b.m3u8",
+			Match:     true,
+			Variables: map[string]string{"path": "a/b", path_template.SuffixVariableName: ".m3u8"},
+			Rewritten: "/a/b.m3u8",
+		}},
+	})
+}
+
+func TestValidateMaxLength(t *testing.T) {
+	resp := Validate(ValidateRequest{Template: "/abcdef"}, path_template.WithMaxLength(4))
+	assert.Equal(t, len(resp.Diagnostics), 1)
+	assert.Equal(t, resp.Diagnostics[0].End, len("/abcdef"))
+}
+
+// TestWasmBuild checks that the playground builds for the browser
+func TestWasmBuild(t *testing.T) {
+	if testing.Short() {
+		t.Skip("building for GOOS=js is slow")
+	}
+	cmd := exec.Command("go", "build", "-o", os.DevNull, "./wasm")
+	cmd.Env = append(os.Environ(), "GOOS=js", "GOARCH=wasm")
+	out, err := cmd.CombinedOutput()
+	assert.NilError(t, err, string(out))
+}
+
+func TestValidateBadRequest(t *testing.T) {
+	srv := httptest.NewServer(NewHandler())
+	defer srv.Close()
+
+	resp, err := http.Post(srv.URL+"/validate", "application/json", strings.NewReader("{"))
+	assert.NilError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, resp.StatusCode, http.StatusBadRequest)
+}
@@ -0,0 +1,31 @@
+//go:build js && wasm
+
+// Command wasm runs the playground validation in the browser, without a server.
+// It defines the global function pathTemplateValidate, taking and returning the JSON bodies
+// of the validate endpoint of the playground handler.
+//
+//	GOOS=js GOARCH=wasm go build -o playground.wasm ./path_template/playground/wasm
+package main
+
+import (
+	"encoding/json"
+	"syscall/js"
+
+	"github.com/bogdan-deac/path-template/path_template/playground"
+)
+
+func main() {
+	js.Global().Set("pathTemplateValidate", js.FuncOf(func(this js.Value, args []js.Value) any {
+		var req playground.ValidateRequest
+		if len(args) != 1 {
+			return `{"error":"Invalid validation request: expected one argument"}`
+		}
+		if err := json.Unmarshal([]byte(args[0].String()), &req); err != nil {
+			b, _ := json.Marshal(map[string]string{"error": "Invalid validation request: " + err.Error()})
+			return string(b)
+		}
+		b, _ := json.Marshal(playground.Validate(req))
+		return string(b)
+	}))
+	select {}
+}
@@ -0,0 +1,67 @@
+// Package presets contains vetted path template and path template rewrite pairs
+// for common media and CDN routing patterns.
+package presets
+
+import (
+	"github.com/bogdan-deac/path-template/path_template"
+)
+
+// Preset is a path template with a matching path template rewrite
+type Preset struct {
+	Name    string
+	Match   string
+	Rewrite string
+}
+
+var (
+	// HLSManifest matches HLS playlists at any depth - /live/channel1/index.m3u8
+	HLSManifest = Preset{
+		Name:    "hls-manifest",
+		Match:   "/{path=**}.m3u8",
+		Rewrite: "/{path}.m3u8",
+	}
+
+	// HLSSegment matches MPEG-TS HLS segments at any depth - /live/channel1/720p/0001.ts
+	HLSSegment = Preset{
+		Name:    "hls-segment",
+		Match:   "/{path=**}.ts",
+		Rewrite: "/{path}.ts",
+	}
+
+	// DASHManifest matches DASH media presentation descriptions - /vod/movie/manifest.mpd
+	DASHManifest = Preset{
+		Name:    "dash-manifest",
+		Match:   "/{path=**}.mpd",
+		Rewrite: "/{path}.mpd",
+	}
+
+	// DASHSegment matches fragmented MP4 segments used by DASH and CMAF HLS - /vod/movie/video/0001.m4s
+	DASHSegment = Preset{
+		Name:    "dash-segment",
+		Match:   "/{path=**}.m4s",
+		Rewrite: "/{path}.m4s",
+	}
+
+	// ImageVariant moves the variant in front of the image path so an image service
+	// can store variants side by side - /images/thumb/a/b.jpg -> /thumb/a/b.jpg
+	ImageVariant = Preset{
+		Name:    "image-variant",
+		Match:   "/images/{variant}/{image=**}",
+		Rewrite: "/{variant}/{image}",
+	}
+)
+
+// All returns every preset
+func All() []Preset {
+	return []Preset{HLSManifest, HLSSegment, DASHManifest, DASHSegment, ImageVariant}
+}
+
+// Validate validates both the match and the rewrite of the preset.
+// Presets shipped by this package are always valid, this is meant for copies modified by callers
+func (p Preset) Validate(opts ...path_template.Option) error {
+	variableNames, err := path_template.ValidatePathTemplate(p.Match, opts...)
+	if err != nil {
+		return err
+	}
+	return path_template.ValidatePathTemplateRewrite(p.Rewrite, variableNames, opts...)
+}
@@ -0,0 +1,25 @@
+package presets
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/bogdan-deac/path-template/path_template"
+)
+
+func TestPresetsAreValid(t *testing.T) {
+	names := map[string]bool{}
+	for _, preset := range All() {
+		assert.NilError(t, preset.Validate(), preset.Name)
+		assert.NilError(t, preset.Validate(path_template.WithStrictRFC3986()), preset.Name)
+		assert.Assert(t, !names[preset.Name], "duplicate preset name %s", preset.Name)
+		names[preset.Name] = true
+	}
+}
+
+func TestModifiedPresetValidation(t *testing.T) {
+	preset := HLSManifest
+	preset.Rewrite = "/{file}.m3u8"
+	assert.Error(t, preset.Validate(), "Variable file in path template rewrite is not present in the path template: /{file}.m3u8")
+}
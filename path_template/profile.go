@@ -0,0 +1,26 @@
+package path_template
+
+import (
+	"context"
+	"runtime/pprof"
+	"strconv"
+)
+
+const (
+	// profileLabelOperation is the pprof label naming the operation of the package burning CPU
+	profileLabelOperation = "path_template.operation"
+	// profileLabelTemplates is the pprof label holding the number of templates of the operation
+	profileLabelTemplates = "path_template.templates"
+)
+
+// profileOperation runs f, a heavy operation on a number of templates, with pprof labels naming it
+// added to the labels of ctx, so production profiles show which route table operation burns CPU.
+// templates < 0 means the number of templates is not known upfront.
+// As with pprof.Do, the goroutine labels are set back to the labels of ctx when f returns.
+func profileOperation(ctx context.Context, operation string, templates int, f func(ctx context.Context)) {
+	labels := []string{profileLabelOperation, operation}
+	if templates >= 0 {
+		labels = append(labels, profileLabelTemplates, strconv.Itoa(templates))
+	}
+	pprof.Do(ctx, pprof.Labels(labels...), f)
+}
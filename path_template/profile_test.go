@@ -0,0 +1,45 @@
+package path_template
+
+import (
+	"context"
+	"runtime/pprof"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestProfileOperation(t *testing.T) {
+	labels := map[string]string{}
+	profileOperation(context.Background(), "test", 3, func(ctx context.Context) {
+		pprof.ForLabels(ctx, func(key, value string) bool {
+			labels[key] = value
+			return true
+		})
+	})
+	assert.DeepEqual(t, labels, map[string]string{
+		profileLabelOperation: "test",
+		profileLabelTemplates: "3",
+	})
+
+	labels = map[string]string{}
+	profileOperation(context.Background(), "unknown-size", -1, func(ctx context.Context) {
+		pprof.ForLabels(ctx, func(key, value string) bool {
+			labels[key] = value
+			return true
+		})
+	})
+	assert.DeepEqual(t, labels, map[string]string{profileLabelOperation: "unknown-size"})
+}
+
+func TestProfileOperationKeepsLabels(t *testing.T) {
+	pprof.Do(context.Background(), pprof.Labels("caller", "yes"), func(ctx context.Context) {
+		labels := map[string]string{}
+		profileOperation(ctx, "test", -1, func(ctx context.Context) {
+			pprof.ForLabels(ctx, func(key, value string) bool {
+				labels[key] = value
+				return true
+			})
+		})
+		assert.DeepEqual(t, labels, map[string]string{"caller": "yes", profileLabelOperation: "test"})
+	})
+}
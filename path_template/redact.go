@@ -0,0 +1,208 @@
+package path_template
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// redactedLiteral replaces the literals of redacted templates
+const redactedLiteral = "<redacted>"
+
+// WithRedactValues replaces the literals of templates quoted in errors and diagnostics with a
+// placeholder, keeping operators, variable names and slashes, so configs with sensitive
+// literals (signed path secrets, internal service names) can be validated in shared logs:
+// /internal/{id}/secret.txt is quoted as /<redacted>/{id}/<redacted>
+func WithRedactValues() Option {
+	return func(o *options) {
+		o.redactValues = true
+	}
+}
+
+// redactTemplate replaces every run of literal characters of a path template, a path template
+// rewrite or a part of them with redactedLiteral. It works on invalid templates too.
+func redactTemplate(text string) string {
+	return mapLiterals(text, func(string) string { return redactedLiteral })
+}
+
+// mapLiterals replaces every run of literal characters of a path template, a path template rewrite
+// or a part of them with f of the run. Operators, variable names and slashes are kept.
+func mapLiterals(text string, f func(literal string) string) string {
+	var sb strings.Builder
+	insideName := false
+	// start of the current run of literal characters, -1 outside of literals
+	literalStart := -1
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		operator := false
+		switch {
+		case c == '{':
+			insideName = true
+			operator = true
+		case c == '}':
+			insideName = false
+			operator = true
+		case c == '=' && insideName:
+			insideName = false
+			operator = true
+		case c == '/' || c == '*' || insideName:
+			operator = true
+		}
+		if !operator {
+			if literalStart < 0 {
+				literalStart = i
+			}
+			continue
+		}
+		if literalStart >= 0 {
+			sb.WriteString(f(text[literalStart:i]))
+			literalStart = -1
+		}
+		sb.WriteByte(c)
+	}
+	if literalStart >= 0 {
+		sb.WriteString(f(text[literalStart:]))
+	}
+	return sb.String()
+}
+
+// variableNameFormats are the formats of the error messages quoting a variable name,
+// the name is kept by redaction
+var variableNameFormats = []string{
+	"Variable name is duplicated: %s",
+	"Reused variable must have the same pattern: %s",
+	"Variable pattern is empty for: %s",
+	"Variable name is shorter than %d characters: %s",
+	"Variable name must start with a letter and contain only alphanumeric characters and underscores: %s",
+	"Variable name exceeds %d characters: %s",
+	"Field path cannot be a variable name: %s",
+}
+
+// quotingFormats are the formats of the error messages quoting a template, a part of it or
+// a value, the quoted text is redacted
+var quotingFormats = []string{
+	"PathTemplate exceeds %d characters: %s",
+	"Path template rewrite exceeds %d characters: %s",
+	"Host rewrite exceeds %d characters: %s",
+	"PathTemplate exceeds %d segments: %s",
+	"PathTemplate contains non-representable characters: %s",
+	"PathTemplate must start with a /: %s",
+	"Replace path template must start with a /: %s",
+	"URL template must contain a scheme: %s",
+	"Scheme must start with a letter and contain only letters, digits, +, - and .: %s",
+	"Validation deadline exceeded: %s",
+	"Bare = not allowed in literal in strict RFC 3986 mode: %s",
+	"Invalid percent-encoding in strict RFC 3986 mode: %s",
+	"Cannot have more than %d variables: %s",
+	"Cannot have variable after text glob (**): %s",
+	"Empty segment not allowed in path template: %s",
+	"Empty segment not allowed in path template rewrite: %s",
+	"Empty variable not allowed in path template rewrite: %s",
+	"Invalid segment in path template: %s",
+	"Invalid variable pattern segment: %s",
+	"Invalid character in path template rewrite: %s",
+	"Invalid character found in path template rewrite: %s",
+	"Nested brackets not allowed in path template: %s",
+	"Nested brackets in not allowed in path template rewrite: %s",
+	"Unmatched { not allowed in path template: %s",
+	"Unmatched } not allowed in path template: %s",
+	"Unmatched { not allowed in path template rewrite: %s",
+	"Unmatched } not allowed in path template rewrite: %s",
+	"Prefixes not allowed before operators: %s",
+	"Prefixes or suffixes not allowed with variable pattern operators: %s",
+	"The suffixed operator must in be the final path component: %s",
+	"Variable name cannot be empty: %s",
+	"Variable pattern cannot start or end with a slash: %s",
+	"Variable %s in path template rewrite is not present in the path template: %s",
+	"Variable %s in host rewrite is not present in the path template: %s",
+	"Variable %s in host rewrite is not bound: %s",
+	"Value of variable %s is not valid in a host: %s",
+	"Invalid host: %s",
+	"Invalid IPv6 address in host: %s",
+	"Invalid character in host: %s",
+	"Unmatched { not allowed in host: %s",
+	"Port must be numeric: %s",
+	"Invalid query parameter name: %s",
+	"Invalid query parameter value: %s",
+}
+
+var (
+	reVariableNameMessages = compileMessagePrefixes(variableNameFormats)
+	reQuotingMessages      = compileMessagePrefixes(quotingFormats)
+)
+
+// compileMessagePrefixes compiles the prefixes of message formats ending with the quoted %s,
+// the other verbs match variable names and numbers
+func compileMessagePrefixes(formats []string) []*regexp.Regexp {
+	prefixes := make([]*regexp.Regexp, 0, len(formats))
+	for _, format := range formats {
+		prefix := regexp.QuoteMeta(strings.TrimSuffix(format, "%s"))
+		prefix = strings.ReplaceAll(prefix, "%d", "[0-9]+")
+		prefix = strings.ReplaceAll(prefix, "%s", "[a-zA-Z0-9_]*")
+		prefixes = append(prefixes, regexp.MustCompile("^"+prefix))
+	}
+	return prefixes
+}
+
+// redactMessage redacts the text quoted at the end of an error message, found after the prefix of
+// its format, unless it is a variable name. Unknown messages are redacted after their first ": ".
+func redactMessage(message string) string {
+	for _, re := range reVariableNameMessages {
+		if re.MatchString(message) {
+			return message
+		}
+	}
+	for _, re := range reQuotingMessages {
+		if loc := re.FindStringIndex(message); loc != nil {
+			return message[:loc[1]] + redactTemplate(message[loc[1]:])
+		}
+	}
+	prefix, quoted, found := strings.Cut(message, ": ")
+	if !found {
+		return message
+	}
+	return prefix + ": " + redactTemplate(quoted)
+}
+
+// redactError redacts the texts quoted in err, keeping ErrDeadlineExceeded and ErrInternal checkable
+// and the types of URLTemplateError and InternalError
+func (o *options) redactError(err error) error {
+	if !o.redactValues || err == nil {
+		return err
+	}
+	switch e := err.(type) {
+	case *URLTemplateError:
+		redacted := &URLTemplateError{prefix: e.prefix}
+		for _, c := range e.Components {
+			redacted.Components = append(redacted.Components, &URLComponentError{Component: c.Component, Err: o.redactError(c.Err)})
+		}
+		return redacted
+	case *InternalError:
+		return &InternalError{Template: redactTemplate(e.Template), Invariant: e.Invariant, Detail: redactMessage(e.Detail)}
+	}
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		errs := []error{}
+		for _, e := range joined.Unwrap() {
+			errs = append(errs, o.redactError(e))
+		}
+		return errors.Join(errs...)
+	}
+
+	message := redactMessage(err.Error())
+	if errors.Is(err, ErrDeadlineExceeded) {
+		return fmt.Errorf("%w%s", ErrDeadlineExceeded, strings.TrimPrefix(message, ErrDeadlineExceeded.Error()))
+	}
+	return errors.New(message)
+}
+
+// redactDiagnostics redacts the texts quoted in the messages of diagnostics
+func (o *options) redactDiagnostics(diagnostics []Diagnostic) []Diagnostic {
+	if !o.redactValues {
+		return diagnostics
+	}
+	for i := range diagnostics {
+		diagnostics[i].Message = redactMessage(diagnostics[i].Message)
+	}
+	return diagnostics
+}
@@ -0,0 +1,123 @@
+package path_template
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestRedactTemplate(t *testing.T) {
+	tt := []struct {
+		text     string
+		expected string
+	}{
+		{text: "/internal/{id}/secret.txt", expected: "/<redacted>/{id}/<redacted>"},
+		{text: "/{path=media/**}.m3u8", expected: "/{path=<redacted>/**}<redacted>"},
+		{text: "/*-v1/**", expected: "/*<redacted>/**"},
+		{text: "/a//{b", expected: "/<redacted>//{b"},
+		{text: "{a}", expected: "{a}"},
+		{text: "c*", expected: "<redacted>*"},
+	}
+	for _, tc := range tt {
+		assert.Equal(t, redactTemplate(tc.text), tc.expected, tc.text)
+	}
+}
+
+func TestRedactMessage(t *testing.T) {
+	tt := []struct {
+		message  string
+		expected string
+	}{
+		{message: "Variable name is duplicated: id", expected: "Variable name is duplicated: id"},
+		{
+			message:  "Variable name must start with a letter and contain only alphanumeric characters and underscores: 1id",
+			expected: "Variable name must start with a letter and contain only alphanumeric characters and underscores: 1id",
+		},
+		{message: "Variable name exceeds 16 characters: abcdefghijklmnopq", expected: "Variable name exceeds 16 characters: abcdefghijklmnopq"},
+		{message: "Variable name cannot be empty: /secret/{}", expected: "Variable name cannot be empty: /<redacted>/{}"},
+		{message: "Invalid segment in path template: secret", expected: "Invalid segment in path template: <redacted>"},
+		{message: "Cannot have more than 1 variables: /secret/{a}/{b}", expected: "Cannot have more than 1 variables: /<redacted>/{a}/{b}"},
+		{message: "No quoted text", expected: "No quoted text"},
+	}
+	for _, tc := range tt {
+		assert.Equal(t, redactMessage(tc.message), tc.expected, tc.message)
+	}
+}
+
+func TestWithRedactValues(t *testing.T) {
+	_, err := ValidatePathTemplate("/token-abc/{id}/*.ts/x", WithRedactValues())
+	assert.Error(t, err, "The suffixed operator must in be the final path component: /<redacted>/{id}/*<redacted>/<redacted>")
+
+	_, err = ValidatePathTemplate("/token-abc/{id}/{a=b*}", WithRedactValues())
+	assert.Error(t, err, "Prefixes or suffixes not allowed with variable pattern operators: <redacted>*")
+
+	_, err = ValidatePathTemplate("/{a}/{a}/secret*", WithRedactValues(), WithCollectAllErrors())
+	assert.Error(t, err, "Variable name is duplicated: a\nPrefixes not allowed before operators: <redacted>*")
+
+	err = ValidatePathTemplateRewrite("/secret/{b}", []string{"a"}, WithRedactValues())
+	assert.Error(t, err, "Variable b in path template rewrite is not present in the path template: /<redacted>/{b}")
+
+	_, err = PathTemplateRewriteVariables("secret", WithRedactValues())
+	assert.Error(t, err, "Replace path template must start with a /: <redacted>")
+
+	_, diagnostics := ParseLenient("/secret//{a}", WithRedactValues())
+	assert.DeepEqual(t, diagnostics, []Diagnostic{{
+		Range:   Range{Start: 8, End: 9},
+		Message: "Empty segment not allowed in path template: <redacted>//{a}",
+	}})
+
+	assert.DeepEqual(t, NewValidator(WithRedactValues()).Update("/secret//{a}"), diagnostics)
+}
+
+func TestWithRedactValuesKeepsSentinels(t *testing.T) {
+	_, err := ValidatePathTemplate("/secret/{a}", WithRedactValues(), WithDeadline(time.Now().Add(-time.Second)))
+	assert.Assert(t, errors.Is(err, ErrDeadlineExceeded))
+	assert.Error(t, err, "Validation deadline exceeded: /<redacted>/{a}")
+}
+
+func TestRedactMessageKnownPrefix(t *testing.T) {
+	tt := []struct {
+		message  string
+		expected string
+	}{
+		{message: "PathTemplate exceeds 8 characters: /secret: x", expected: "PathTemplate exceeds 8 characters: /<redacted>"},
+		{message: "Host rewrite exceeds 8 characters: secret.example.com", expected: "Host rewrite exceeds 8 characters: <redacted>"},
+		{message: "Invalid host: secret: x", expected: "Invalid host: <redacted>"},
+		{
+			message:  "Variable b in host rewrite is not present in the path template: {b}.secret",
+			expected: "Variable b in host rewrite is not present in the path template: {b}<redacted>",
+		},
+		{message: "Variable name is duplicated: a", expected: "Variable name is duplicated: a"},
+	}
+	for _, tc := range tt {
+		assert.Equal(t, redactMessage(tc.message), tc.expected, tc.message)
+	}
+}
+
+func TestWithRedactValuesURLTemplate(t *testing.T) {
+	_, err := ValidateURLTemplate("https://secret host/{a}?token=x y", WithRedactValues(), WithErrorPrefix("spec.url"))
+	var urlErr *URLTemplateError
+	assert.Assert(t, errors.As(err, &urlErr))
+	assert.Assert(t, !strings.Contains(err.Error(), "secret"), err.Error())
+	assert.Assert(t, !strings.Contains(err.Error(), "x y"), err.Error())
+	for _, line := range strings.Split(err.Error(), "\n") {
+		assert.Assert(t, strings.HasPrefix(line, "spec.url: "), line)
+	}
+}
+
+func TestWithRedactValuesInternalError(t *testing.T) {
+	o := newOptions([]Option{WithRedactValues()})
+	err := o.redactError(&InternalError{
+		Template:  "/secret/{a}",
+		Invariant: InvariantLenientParseValid,
+		Detail:    "Invalid segment in path template: secret: x",
+	})
+	var internalErr *InternalError
+	assert.Assert(t, errors.As(err, &internalErr))
+	assert.Assert(t, errors.Is(err, ErrInternal))
+	assert.Equal(t, internalErr.Template, "/<redacted>/{a}")
+	assert.Equal(t, internalErr.Detail, "Invalid segment in path template: <redacted>")
+}
@@ -0,0 +1,104 @@
+package path_template
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ErrNoMatch is returned when rewriting a request path the path template does not match
+var ErrNoMatch = errors.New("Request path not matched by path template")
+
+// Rewrite is a compiled path template rewrite, expanded with the values of a matched request path
+type Rewrite struct {
+	text  string
+	parts []rewritePart
+}
+
+// rewritePart is a literal or a variable of a rewrite
+type rewritePart struct {
+	literal  string
+	variable string
+}
+
+// ParseRewrite validates the syntax of a path template rewrite with opts and compiles it.
+// Its variables are checked against the path template it rewrites with when rewriting.
+func ParseRewrite(rewrite string, opts ...Option) (*Rewrite, error) {
+	if _, err := PathTemplateRewriteVariables(rewrite, opts...); err != nil {
+		return nil, err
+	}
+	r := &Rewrite{text: rewrite}
+	rest := rewrite
+	for len(rest) > 0 {
+		start := strings.IndexByte(rest, '{')
+		if start < 0 {
+			r.parts = append(r.parts, rewritePart{literal: rest})
+			break
+		}
+		end := start + strings.IndexByte(rest[start:], '}')
+		if start > 0 {
+			r.parts = append(r.parts, rewritePart{literal: rest[:start]})
+		}
+		r.parts = append(r.parts, rewritePart{variable: rest[start+1 : end]})
+		rest = rest[end+1:]
+	}
+	return r, nil
+}
+
+// String returns the path template rewrite r was parsed from
+func (r *Rewrite) String() string {
+	return r.text
+}
+
+// RewriteTo matches a request path and writes it rewritten with rewrite to w, as envoy rewrites
+// the path of a matched request, without building the values of the variables.
+// It fails with ErrNoMatch when t doesn't match requestPath and when rewrite references
+// a variable t doesn't have.
+func (t *Template) RewriteTo(w io.Writer, rewrite *Rewrite, requestPath string) error {
+	if i := strings.IndexAny(requestPath, "?#"); i >= 0 {
+		requestPath = requestPath[:i]
+	}
+	submatches := t.re.FindStringSubmatchIndex(requestPath)
+	if submatches == nil || !t.reusedValuesEqual(requestPath, submatches) {
+		return fmt.Errorf("%w: %s", ErrNoMatch, excerpt(requestPath))
+	}
+	for _, part := range rewrite.parts {
+		value := part.literal
+		switch {
+		case len(part.variable) == 0:
+		case part.variable == SuffixVariableName && len(t.suffix) > 0:
+			value = t.suffix
+		default:
+			i := t.re.SubexpIndex(part.variable)
+			if i < 0 {
+				return fmt.Errorf("Variable %s in path template rewrite is not present in the path template: %s", excerpt(part.variable), excerpt(rewrite.text))
+			}
+			// variables of missing optional segments expand to nothing
+			if submatches[2*i] >= 0 {
+				value = requestPath[submatches[2*i]:submatches[2*i+1]]
+			}
+		}
+		if _, err := io.WriteString(w, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// reusedValuesEqual reports whether every occurrence of a reused variable has the same value
+func (t *Template) reusedValuesEqual(requestPath string, submatches []int) bool {
+	names := t.re.SubexpNames()
+	for i, name := range names {
+		if len(name) == 0 || submatches[2*i] < 0 {
+			continue
+		}
+		for j := range i {
+			if names[j] == name && submatches[2*j] >= 0 &&
+				requestPath[submatches[2*i]:submatches[2*i+1]] != requestPath[submatches[2*j]:submatches[2*j+1]] {
+				return false
+			}
+		}
+	}
+	return true
+}
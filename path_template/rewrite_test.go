@@ -0,0 +1,108 @@
+package path_template
+
+import (
+	"bytes"
+	"errors"
+	"slices"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestParseRewrite(t *testing.T) {
+	r, err := ParseRewrite("/{v}/a/{path}")
+	assert.NilError(t, err)
+	assert.Equal(t, r.String(), "/{v}/a/{path}")
+	assert.Assert(t, slices.Equal(r.parts, []rewritePart{{literal: "/"}, {variable: "v"}, {literal: "/a/"}, {variable: "path"}}), "%v", r.parts)
+
+	_, err = ParseRewrite("v/{path}")
+	assert.Error(t, err, "Replace path template must start with a /: v/{path}")
+}
+
+func TestTemplateRewriteTo(t *testing.T) {
+	tt := []struct {
+		template    string
+		opts        []Option
+		rewrite     string
+		requestPath string
+		expected    string
+	}{
+		{template: "/api/{v}/{path=**}", rewrite: "/{v}/{path}", requestPath: "/api/v1/a/b?x=1", expected: "/v1/a/b"},
+		{template: "/api/{v}/{path=**}", rewrite: "/static", requestPath: "/api/v1/a", expected: "/static"},
+		{template: "/{a}/{b}", rewrite: "/{b}/{a}/{b}", requestPath: "/x/y", expected: "/y/x/y"},
+		{
+			template:    "/videos/{path=**}.m3u8",
+			opts:        []Option{WithSuffixVariable()},
+			rewrite:     "/{path}{__suffix}",
+			requestPath: "/videos/a/b.m3u8",
+			expected:    "/a/b.m3u8",
+		},
+		{
+			template:    "/users/{id}[/{format}]",
+			opts:        []Option{WithOptionalSegments()},
+			rewrite:     "/{id}/{format}",
+			requestPath: "/users/42",
+			expected:    "/42/",
+		},
+		{
+			template:    "/{id}/compare/{id}",
+			opts:        []Option{WithVariableReuse()},
+			rewrite:     "/{id}",
+			requestPath: "/42/compare/42",
+			expected:    "/42",
+		},
+	}
+	for _, tc := range tt {
+		tmpl, err := Parse(tc.template, tc.opts...)
+		assert.NilError(t, err, tc.template)
+		rewrite, err := ParseRewrite(tc.rewrite, tc.opts...)
+		assert.NilError(t, err, tc.rewrite)
+
+		var buf bytes.Buffer
+		assert.NilError(t, tmpl.RewriteTo(&buf, rewrite, tc.requestPath))
+		assert.Equal(t, buf.String(), tc.expected)
+
+		// same as expanding the rewrite with the values of the variables
+		variables, match := tmpl.Match(tc.requestPath)
+		assert.Assert(t, match)
+		assert.Equal(t, buf.String(), expandRewrite(tc.rewrite, variables))
+	}
+}
+
+func TestTemplateRewriteToErrors(t *testing.T) {
+	tmpl, err := Parse("/api/{v}")
+	assert.NilError(t, err)
+	rewrite, err := ParseRewrite("/{v}")
+	assert.NilError(t, err)
+
+	err = tmpl.RewriteTo(&bytes.Buffer{}, rewrite, "/other/v1")
+	assert.Assert(t, errors.Is(err, ErrNoMatch))
+	assert.Error(t, err, "Request path not matched by path template: /other/v1")
+
+	reused, err := Parse("/{id}/compare/{id}", WithVariableReuse())
+	assert.NilError(t, err)
+	assert.Assert(t, errors.Is(reused.RewriteTo(&bytes.Buffer{}, rewrite, "/42/compare/43"), ErrNoMatch))
+
+	unbound, err := ParseRewrite("/{w}")
+	assert.NilError(t, err)
+	err = tmpl.RewriteTo(&bytes.Buffer{}, unbound, "/api/v1")
+	assert.Error(t, err, "Variable w in path template rewrite is not present in the path template: /{w}")
+}
+
+func TestTemplateRewriteToAllocations(t *testing.T) {
+	tmpl, err := Parse("/api/{v}/{path=**}")
+	assert.NilError(t, err)
+	rewrite, err := ParseRewrite("/{v}/{path}")
+	assert.NilError(t, err)
+
+	var buf bytes.Buffer
+	buf.Grow(64)
+	rewriteTo := testing.AllocsPerRun(100, func() {
+		buf.Reset()
+		_ = tmpl.RewriteTo(&buf, rewrite, "/api/v1/a/b")
+	})
+	rewriteString := testing.AllocsPerRun(100, func() {
+		_, _ = tmpl.Rewrite("/api/v1/a/b", "/{v}/{path}")
+	})
+	assert.Assert(t, rewriteTo < rewriteString, "%v >= %v", rewriteTo, rewriteString)
+}
@@ -0,0 +1,96 @@
+package path_template
+
+import "encoding/json"
+
+const (
+	sarifVersion  = "2.1.0"
+	sarifSchema   = "https://json.schemastore.org/sarif-2.1.0.json"
+	sarifToolName = "path-template"
+	sarifToolURI  = "https://github.com/bogdan-deac/path-template"
+)
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string `json:"name"`
+	InformationURI string `json:"informationUri"`
+}
+
+type sarifResult struct {
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           sarifRegion           `json:"region"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn"`
+	EndColumn   int `json:"endColumn"`
+	CharOffset  int `json:"charOffset"`
+	CharLength  int `json:"charLength"`
+}
+
+// ToSARIF encodes diagnostics as a SARIF 2.1.0 log with a single run, so tools embedding the
+// validator can emit standard static analysis output. Every diagnostic is an error result
+// located by its range in the template, which is a single line starting at column 1, of the
+// artifact at artifactURI, ie the file or the URI of the route the template was read from.
+func ToSARIF(artifactURI string, diags []Diagnostic) ([]byte, error) {
+	results := make([]sarifResult, 0, len(diags))
+	for _, d := range diags {
+		results = append(results, sarifResult{
+			Level:   "error",
+			Message: sarifMessage{Text: d.Message},
+			Locations: []sarifLocation{{
+				PhysicalLocation: sarifPhysicalLocation{
+					ArtifactLocation: sarifArtifactLocation{URI: artifactURI},
+					Region: sarifRegion{
+						StartLine:   1,
+						StartColumn: d.Range.Start + 1,
+						EndColumn:   d.Range.End + 1,
+						CharOffset:  d.Range.Start,
+						CharLength:  d.Range.End - d.Range.Start,
+					},
+				},
+			}},
+		})
+	}
+
+	return json.MarshalIndent(sarifLog{
+		Schema:  sarifSchema,
+		Version: sarifVersion,
+		Runs: []sarifRun{{
+			Tool:    sarifTool{Driver: sarifDriver{Name: sarifToolName, InformationURI: sarifToolURI}},
+			Results: results,
+		}},
+	}, "", "  ")
+}
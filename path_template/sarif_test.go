@@ -0,0 +1,70 @@
+package path_template
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/xeipuuv/gojsonschema"
+	"gotest.tools/v3/assert"
+)
+
+func TestToSARIF(t *testing.T) {
+	_, diags := ParseLenient("/a/{b}/{b}")
+	out, err := ToSARIF("routes.yaml", diags)
+	assert.NilError(t, err)
+
+	var log map[string]any
+	assert.NilError(t, json.Unmarshal(out, &log))
+	assert.Equal(t, log["version"], "2.1.0")
+	runs := log["runs"].([]any)
+	assert.Equal(t, len(runs), 1)
+	run := runs[0].(map[string]any)
+	assert.Equal(t, run["tool"].(map[string]any)["driver"].(map[string]any)["name"], "path-template")
+
+	results := run["results"].([]any)
+	assert.Equal(t, len(results), 1)
+	result := results[0].(map[string]any)
+	assert.Equal(t, result["level"], "error")
+	assert.Equal(t, result["message"].(map[string]any)["text"], "Variable name is duplicated: b")
+	location := result["locations"].([]any)[0].(map[string]any)["physicalLocation"].(map[string]any)
+	assert.DeepEqual(t, location["artifactLocation"], map[string]any{"uri": "routes.yaml"})
+	region := location["region"]
+	assert.DeepEqual(t, region, map[string]any{
+		"startLine":   1.0,
+		"startColumn": 8.0,
+		"endColumn":   11.0,
+		"charOffset":  7.0,
+		"charLength":  3.0,
+	})
+}
+
+func TestToSARIFEmpty(t *testing.T) {
+	out, err := ToSARIF("routes.yaml", nil)
+	assert.NilError(t, err)
+
+	var log struct {
+		Runs []struct {
+			Results []any `json:"results"`
+		} `json:"runs"`
+	}
+	assert.NilError(t, json.Unmarshal(out, &log))
+	assert.Equal(t, len(log.Runs), 1)
+	// an empty array rather than null, a run without results is a clean run
+	assert.Assert(t, log.Runs[0].Results != nil)
+	assert.Equal(t, len(log.Runs[0].Results), 0)
+}
+
+// TestToSARIFSchema validates the output against the SARIF 2.1.0 schema
+func TestToSARIFSchema(t *testing.T) {
+	schema, err := gojsonschema.NewSchema(gojsonschema.NewReferenceLoader("file://./testdata/sarif-schema-2.1.0.json"))
+	assert.NilError(t, err)
+
+	_, diags := ParseLenient("/a//{b}/{b}", WithCollectAllErrors())
+	for _, d := range [][]Diagnostic{nil, diags} {
+		out, err := ToSARIF("file:///etc/envoy/routes.yaml", d)
+		assert.NilError(t, err)
+		result, err := schema.Validate(gojsonschema.NewBytesLoader(out))
+		assert.NilError(t, err)
+		assert.Assert(t, result.Valid(), "%v", result.Errors())
+	}
+}
@@ -0,0 +1,50 @@
+package path_template
+
+import (
+	"cmp"
+	"slices"
+)
+
+// Precedence returns the kinds of segment matchers from the most to the least specific:
+// literals, path globs (*) then text globs (**). Variables rank as the segments of their
+// pattern, {id} as a path glob and {path=**} as a text glob.
+func Precedence() []NodeKind {
+	return []NodeKind{NodeLiteral, NodePathGlob, NodeTextGlob}
+}
+
+// tokenRank is the position of the kind of a token in Precedence
+func tokenRank(t token) int {
+	kind := NodeLiteral
+	switch t.kind {
+	case tokenPathGlob:
+		kind = NodePathGlob
+	case tokenTextGlob:
+		kind = NodeTextGlob
+	}
+	return slices.Index(Precedence(), kind)
+}
+
+// CompareSpecificity orders path templates by specificity, returning a negative number when a is more
+// specific than b, a positive number when b is more specific than a and 0 when they rank the same.
+// Segment matchers are compared in order following Precedence and the first difference decides.
+// When all compared matchers rank the same, a template with a suffix is more specific than one without,
+// a longer suffix more specific than a shorter one and then the template with more segments is
+// the most specific. Variable names and literal values do not matter: /a/{id} and /b/* rank the same.
+func CompareSpecificity(a, b string) (int, error) {
+	parsedA, parsedB, err := parseValidPair(a, b, nil)
+	if err != nil {
+		return 0, err
+	}
+	tokensA, suffixA := flattenTokens(parsedA)
+	tokensB, suffixB := flattenTokens(parsedB)
+
+	for i := 0; i < len(tokensA) && i < len(tokensB); i++ {
+		if c := cmp.Compare(tokenRank(tokensA[i]), tokenRank(tokensB[i])); c != 0 {
+			return c, nil
+		}
+	}
+	if c := cmp.Compare(len(suffixB), len(suffixA)); c != 0 {
+		return c, nil
+	}
+	return cmp.Compare(len(tokensB), len(tokensA)), nil
+}
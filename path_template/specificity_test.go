@@ -0,0 +1,41 @@
+package path_template
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestPrecedence(t *testing.T) {
+	assert.DeepEqual(t, Precedence(), []NodeKind{NodeLiteral, NodePathGlob, NodeTextGlob})
+	// callers can't alter the rules
+	Precedence()[0] = NodeTextGlob
+	assert.Equal(t, Precedence()[0], NodeLiteral)
+}
+
+func TestCompareSpecificity(t *testing.T) {
+	tt := []struct {
+		a, b     string
+		expected int
+	}{
+		{a: "/a/b", b: "/a/*", expected: -1},
+		{a: "/a/*", b: "/a/b", expected: 1},
+		{a: "/a/{id}", b: "/a/**", expected: -1},
+		{a: "/a/{path=**}", b: "/a/{id}", expected: 1},
+		{a: "/a/{x=b/*}", b: "/a/*/*", expected: -1},
+		{a: "/a/*.ts", b: "/a/*", expected: -1},
+		{a: "/a/*.ts", b: "/a/*-v1.ts", expected: 1},
+		{a: "/a/*/c", b: "/a/*", expected: -1},
+		{a: "/a/", b: "/a", expected: -1},
+		{a: "/a/{id}", b: "/b/*", expected: 0},
+		{a: "/**", b: "/{path=**}", expected: 0},
+	}
+	for _, tc := range tt {
+		c, err := CompareSpecificity(tc.a, tc.b)
+		assert.NilError(t, err)
+		assert.Equal(t, c, tc.expected, "%s vs %s", tc.a, tc.b)
+	}
+
+	_, err := CompareSpecificity("/a", "/{a")
+	assert.ErrorContains(t, err, "")
+}
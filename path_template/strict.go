@@ -0,0 +1,40 @@
+package path_template
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// rePctEncoded matches a percent-encoding as produced by RFC 3986 compliant
+// generators: a % followed by two uppercase hex digits
+var rePctEncoded = regexp.MustCompile(`^%[0-9A-F]{2}`)
+
+// WithStrictRFC3986 validates literals strictly per RFC 3986.
+// Bare = characters are only allowed as the variable pattern separator ({foo=bar})
+// and percent-encodings must be well formed and use uppercase hex digits (%2F, not %2f).
+// Useful for templates generating URLs consumed by picky clients.
+func WithStrictRFC3986() Option {
+	return func(o *options) {
+		o.strictRFC3986 = true
+	}
+}
+
+// validateStrictLiteral checks a literal against the strict RFC 3986 rules.
+// The literal is assumed to already contain only valid pchars
+func validateStrictLiteral(literal string) error {
+	if strings.ContainsRune(literal, '=') {
+		return fmt.Errorf("Bare = not allowed in literal in strict RFC 3986 mode: %s", excerpt(literal))
+	}
+	for i := 0; i < len(literal); i++ {
+		if literal[i] != '%' {
+			continue
+		}
+		if !rePctEncoded.MatchString(literal[i:]) {
+			return fmt.Errorf("Invalid percent-encoding in strict RFC 3986 mode: %s", excerpt(literal))
+		}
+		// skip the two hex digits
+		i += 2
+	}
+	return nil
+}
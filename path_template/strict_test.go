@@ -0,0 +1,93 @@
+package path_template
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestStrictRFC3986Success(t *testing.T) {
+	validPathTemplates := []string{
+		"/a/b", "/a/%2F/b", "/{foo=bar}", "/{foo=a/%2Fb/*}", "/**.m3u8",
+		"/{path=**}%20suffix", "/*aA0-._~%20!$&'()+,;:@",
+	}
+	for _, path := range validPathTemplates {
+		_, err := ValidatePathTemplate(path, WithStrictRFC3986())
+		assert.NilError(t, err)
+	}
+}
+
+func TestStrictRFC3986Failure(t *testing.T) {
+	tt := []struct {
+		path string
+		err  string
+	}{
+		{
+			path: "/a=b",
+			err:  "Bare = not allowed in literal in strict RFC 3986 mode: a=b",
+		},
+		{
+			path: "/*=",
+			err:  "Bare = not allowed in literal in strict RFC 3986 mode: =",
+		},
+		{
+			path: "/{foo=a=b}",
+			err:  "Bare = not allowed in literal in strict RFC 3986 mode: a=b",
+		},
+		{
+			path: "/a/%2f",
+			err:  "Invalid percent-encoding in strict RFC 3986 mode: %2f",
+		},
+		{
+			path: "/a/%2",
+			err:  "Invalid percent-encoding in strict RFC 3986 mode: %2",
+		},
+		{
+			path: "/a/%%20",
+			err:  "Invalid percent-encoding in strict RFC 3986 mode: %%20",
+		},
+		{
+			path: "/{foo=%zz/*}",
+			err:  "Invalid percent-encoding in strict RFC 3986 mode: %zz",
+		},
+		{
+			path: "/{foo}%a0",
+			err:  "Invalid percent-encoding in strict RFC 3986 mode: %a0",
+		},
+	}
+	for _, tc := range tt {
+		// the relaxed default mode accepts all of these
+		_, err := ValidatePathTemplate(tc.path)
+		assert.NilError(t, err)
+
+		_, err = ValidatePathTemplate(tc.path, WithStrictRFC3986())
+		assert.Error(t, err, tc.err)
+	}
+}
+
+func TestStrictRFC3986Rewrite(t *testing.T) {
+	tt := []struct {
+		rewrite string
+		err     string
+	}{
+		{
+			rewrite: "/{var1}/%2F",
+		},
+		{
+			rewrite: "/{var1}=",
+			err:     "Bare = not allowed in literal in strict RFC 3986 mode: =",
+		},
+		{
+			rewrite: "/a%2f{var1}",
+			err:     "Invalid percent-encoding in strict RFC 3986 mode: /a%2f",
+		},
+	}
+	for _, tc := range tt {
+		err := ValidatePathTemplateRewrite(tc.rewrite, []string{"var1"}, WithStrictRFC3986())
+		if tc.err == "" {
+			assert.NilError(t, err)
+		} else {
+			assert.Error(t, err, tc.err)
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package path_template
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SuffixVariableName is the implicit variable holding the suffix of a suffixed operator
+// when WithSuffixVariable is used, ie .m3u8 for /{path=**}.m3u8
+// It starts with underscores so it can never clash with a user defined variable name.
+const SuffixVariableName = "__suffix"
+
+// WithSuffixVariable exposes the suffix of a suffixed operator as the implicit
+// variable SuffixVariableName, so rewrites can reference it - /{path=**}.m3u8 -> /hls/{path}{__suffix}.
+// The implicit variable does not count towards the variable limit.
+// Both the path template and the path template rewrite must be validated with this option.
+func WithSuffixVariable() Option {
+	return func(o *options) {
+		o.suffixVariable = true
+	}
+}
+
+// SuffixRule is a row of the decision table of suffixed operators, see SuffixRules
+type SuffixRule struct {
+	// Operator is the kind of the suffixed operator: path glob, text glob or variable
+	Operator NodeKind
+	// Final is true when the suffixed operator is the final segment of the path template
+	Final bool
+	// Allowed is true when the path template is valid in that case
+	Allowed bool
+	// Reason explains the rule
+	Reason string
+}
+
+// SuffixRules returns the decision table of suffixed operators - *.ts, **.m3u8 or {name}-v1.
+// Besides it, a suffix is made of literal characters only and the operators of
+// a variable pattern can't have suffixes: {name=*.ts} is invalid, {name=*}.ts is valid.
+func SuffixRules() []SuffixRule {
+	rules := []SuffixRule{}
+	for _, operator := range []NodeKind{NodePathGlob, NodeTextGlob, NodeVariable} {
+		rules = append(rules,
+			SuffixRule{
+				Operator: operator,
+				Final:    true,
+				Allowed:  true,
+				Reason:   "the suffix ends the path template",
+			},
+			SuffixRule{
+				Operator: operator,
+				Final:    false,
+				Allowed:  false,
+				Reason:   "the suffixed operator must be the final path component",
+			},
+		)
+	}
+	return rules
+}
+
+// SuffixExplanation pinpoints a suffixed operator which is not the final segment of a path template
+type SuffixExplanation struct {
+	// Suffixed is the suffixed operator blocking the segments after it
+	Suffixed Node
+	// Following are the segments after the suffixed operator
+	Following []Node
+	// Explanation is a human readable description of the problem
+	Explanation string
+	// Suggestion is a valid reordering of the template with the suffixed operator last,
+	// empty when there is none. Note that it matches different paths.
+	Suggestion string
+}
+
+// ExplainSuffixError explains why a suffixed operator makes a path template invalid, reporting
+// false when the template has no misplaced suffixed operator. When several operators are
+// suffixed, the first one is explained.
+func ExplainSuffixError(template string) (SuffixExplanation, bool) {
+	parsed, _ := ParseLenient(template)
+	for i, node := range parsed.Segments {
+		if len(node.Suffix) == 0 || i == len(parsed.Segments)-1 {
+			continue
+		}
+
+		following := parsed.Segments[i+1:]
+		texts := make([]string, 0, len(following))
+		for _, n := range following {
+			texts = append(texts, n.Text)
+		}
+		explanation := SuffixExplanation{
+			Suffixed:  node,
+			Following: following,
+			Explanation: fmt.Sprintf("%s has the suffix %s so it must be the final path component, but it is followed by %s",
+				node.Text, node.Suffix, strings.Join(texts, "/")),
+		}
+
+		// the suffixed operator moved last
+		reordered := make([]string, 0, len(parsed.Segments))
+		for j, n := range parsed.Segments {
+			if j != i {
+				reordered = append(reordered, n.Text)
+			}
+		}
+		suggestion := "/" + strings.Join(append(reordered, node.Text), "/")
+		if _, err := ValidatePathTemplate(suggestion); err == nil {
+			explanation.Suggestion = suggestion
+		}
+		return explanation, true
+	}
+	return SuffixExplanation{}, false
+}
@@ -0,0 +1,95 @@
+package path_template
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestSuffixVariable(t *testing.T) {
+	tt := []struct {
+		path      string
+		variables []string
+	}{
+		{
+			path:      "/{path=**}.m3u8",
+			variables: []string{"path", SuffixVariableName},
+		},
+		{
+			path:      "/media/*.m4s",
+			variables: []string{SuffixVariableName},
+		},
+		{
+			path:      "/{v1}/{v2}/{v3}/{v4}/{v5}-suffix",
+			variables: []string{"v1", "v2", "v3", "v4", "v5", SuffixVariableName},
+		},
+		{
+			path:      "/{path=**}",
+			variables: []string{"path"},
+		},
+	}
+	for _, tc := range tt {
+		variables, err := ValidatePathTemplate(tc.path, WithSuffixVariable())
+		assert.NilError(t, err)
+		assert.DeepEqual(t, variables, tc.variables)
+	}
+}
+
+func TestSuffixVariableRewrite(t *testing.T) {
+	variables, err := ValidatePathTemplate("/{path=**}.m3u8", WithSuffixVariable())
+	assert.NilError(t, err)
+
+	err = ValidatePathTemplateRewrite("/hls/{path}{__suffix}", variables, WithSuffixVariable())
+	assert.NilError(t, err)
+
+	// without the option the name is invalid
+	err = ValidatePathTemplateRewrite("/hls/{path}{__suffix}", variables)
+	assert.Error(t, err, "Variable name must start with a letter and contain only alphanumeric characters and underscores: __suffix")
+
+	// templates without a suffix do not expose the variable
+	variables, err = ValidatePathTemplate("/{path=**}", WithSuffixVariable())
+	assert.NilError(t, err)
+	err = ValidatePathTemplateRewrite("/{path}{__suffix}", variables, WithSuffixVariable())
+	assert.Error(t, err, "Variable __suffix in path template rewrite is not present in the path template: /{path}{__suffix}")
+}
+
+func TestSuffixRules(t *testing.T) {
+	rules := SuffixRules()
+	assert.Equal(t, len(rules), 6)
+
+	// the table agrees with the validator
+	for _, rule := range rules {
+		operator := map[NodeKind]string{NodePathGlob: "*", NodeTextGlob: "**", NodeVariable: "{v}"}[rule.Operator]
+		template := "/a/" + operator + ".ts"
+		if !rule.Final {
+			template += "/b"
+		}
+		_, err := ValidatePathTemplate(template)
+		assert.Equal(t, err == nil, rule.Allowed, template)
+	}
+}
+
+func TestExplainSuffixError(t *testing.T) {
+	explanation, ok := ExplainSuffixError("/media/{id}.m3u8/{quality}")
+	assert.Assert(t, ok)
+	assert.Equal(t, explanation.Suffixed.Text, "{id}.m3u8")
+	assert.Equal(t, explanation.Suffixed.Suffix, ".m3u8")
+	assert.Equal(t, len(explanation.Following), 1)
+	assert.Equal(t, explanation.Explanation,
+		"{id}.m3u8 has the suffix .m3u8 so it must be the final path component, but it is followed by {quality}")
+	assert.Equal(t, explanation.Suggestion, "/media/{quality}/{id}.m3u8")
+
+	// no valid reordering, a text glob can't follow a text glob
+	explanation, ok = ExplainSuffixError("/**.ts/**")
+	assert.Assert(t, ok)
+	assert.Equal(t, explanation.Suggestion, "")
+
+	explanation, ok = ExplainSuffixError("/a/*-v1/{b}-v2/c")
+	assert.Assert(t, ok)
+	assert.Equal(t, explanation.Suffixed.Text, "*-v1")
+
+	for _, template := range []string{"/media/{id}.m3u8", "/a/b", "/a/{b"} {
+		_, ok := ExplainSuffixError(template)
+		assert.Assert(t, !ok, template)
+	}
+}
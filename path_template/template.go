@@ -0,0 +1,176 @@
+package path_template
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"unsafe"
+)
+
+const (
+	// rePathGlob matches what a path glob (*) matches, a non empty path segment
+	rePathGlob = `[` + validLiteralSymbolsReS + `]+`
+	// reTextGlob matches what a text glob (**) matches, any number of pchars and slashes
+	reTextGlob = `[` + validLiteralSymbolsReS + `/]*`
+)
+
+// Template is a compiled path template, matching request paths the way envoy does
+type Template struct {
+	text string
+	re   *regexp.Regexp
+	// suffix bound to SuffixVariableName, set with WithSuffixVariable only
+	suffix string
+	// hooks and traceContext are called around matches, set with WithHooks and WithTraceRegions
+	hooks        Hooks
+	traceContext context.Context
+}
+
+// Parse validates a path template with opts and compiles it for matching request paths
+func Parse(path string, opts ...Option) (*Template, error) {
+	parsed, err := parseValid(path, opts)
+	if err != nil {
+		return nil, err
+	}
+	re, err := compileTemplateRegexp(parsed)
+	if err != nil {
+		return nil, err
+	}
+	o := newOptions(opts)
+	t := &Template{text: path, re: re, hooks: o.hooks, traceContext: o.traceContext}
+	if o.suffixVariable && len(parsed.Segments) > 0 {
+		t.suffix = parsed.Segments[len(parsed.Segments)-1].Suffix
+	}
+	return t, nil
+}
+
+// compileTemplateRegexp compiles the regular expression of a valid parsed template
+func compileTemplateRegexp(parsed *ParsedTemplate) (*regexp.Regexp, error) {
+	re, err := regexp.Compile(templateRegexp(parsed))
+	if err != nil {
+		return nil, internalError(parsed.Text, InvariantTemplateRegexp, err.Error())
+	}
+	return re, nil
+}
+
+// templateRegexp converts a valid parsed template into a regular expression, as envoy does,
+// where each variable is a named group. Optional segments make an optional group.
+// Example: /a/{b=c/*}/**.m3u8 -> ^/a/(?P<b>c/[...]+)/[.../]*\.m3u8$
+func templateRegexp(parsed *ParsedTemplate) string {
+	var sb strings.Builder
+	sb.WriteString("^")
+	for i, node := range parsed.Segments {
+		if i == parsed.Optional && i > 0 {
+			sb.WriteString("(?:")
+		}
+		sb.WriteString("/")
+		sb.WriteString(regexp.QuoteMeta(node.Prefix))
+		switch node.Kind {
+		case NodeLiteral:
+			sb.WriteString(regexp.QuoteMeta(node.Text))
+		case NodePathGlob:
+			sb.WriteString(rePathGlob)
+		case NodeTextGlob:
+			sb.WriteString(reTextGlob)
+		case NodeVariable:
+			// {foo} is the same as {foo=*}
+			pattern := node.Pattern
+			if len(pattern) == 0 {
+				pattern = textGlob
+			}
+			patternSegments := []string{}
+			for _, patternSegment := range strings.Split(pattern, "/") {
+				switch patternSegment {
+				case textGlob:
+					patternSegments = append(patternSegments, rePathGlob)
+				case pathGlob:
+					patternSegments = append(patternSegments, reTextGlob)
+				default:
+					patternSegments = append(patternSegments, regexp.QuoteMeta(patternSegment))
+				}
+			}
+			sb.WriteString("(?P<" + node.Name + ">" + strings.Join(patternSegments, "/") + ")")
+		}
+		sb.WriteString(regexp.QuoteMeta(node.Suffix))
+	}
+	// / and /a/ end with an empty segment
+	if strings.HasSuffix(strings.TrimSuffix(parsed.Text, "]"), "/") {
+		sb.WriteString("/")
+	}
+	if parsed.Optional > 0 {
+		sb.WriteString(")?")
+	}
+	sb.WriteString("$")
+	return sb.String()
+}
+
+// String returns the path template t was parsed from
+func (t *Template) String() string {
+	return t.text
+}
+
+// Match matches a request path, returning the values of the variables of the template.
+// As in envoy, the query string and the fragment are not matched and values are not decoded:
+// /users/{id} matches /users/a%20b?x=1 with id bound to a%20b.
+// Variables of missing optional segments are not bound, and every occurrence of a reused
+// variable must have the same value.
+func (t *Template) Match(requestPath string) (map[string]string, bool) {
+	end := t.startMatch(requestPath)
+	variables, match := t.match(requestPath, false)
+	end(match)
+	return variables, match
+}
+
+// MatchBytes is Match for a request path held in a byte slice, as received from HTTP stacks.
+// requestPath is matched without copying it and must not be modified during the call,
+// the returned values, as well as the request paths given to hooks, don't share memory with it.
+func (t *Template) MatchBytes(requestPath []byte) (map[string]string, bool) {
+	if t.hooks.OnMatchStart != nil || t.hooks.OnMatchEnd != nil {
+		// hooks may keep the request path
+		return t.Match(string(requestPath))
+	}
+	end := t.startMatch("")
+	variables, match := t.match(unsafe.String(unsafe.SliceData(requestPath), len(requestPath)), true)
+	end(match)
+	return variables, match
+}
+
+// match matches requestPath, copying it once before binding values when clone is set
+func (t *Template) match(requestPath string, clone bool) (map[string]string, bool) {
+	if i := strings.IndexAny(requestPath, "?#"); i >= 0 {
+		requestPath = requestPath[:i]
+	}
+	submatches := t.re.FindStringSubmatchIndex(requestPath)
+	if submatches == nil {
+		return nil, false
+	}
+	if clone {
+		requestPath = strings.Clone(requestPath)
+	}
+	variables := map[string]string{}
+	for i, name := range t.re.SubexpNames() {
+		start, end := submatches[2*i], submatches[2*i+1]
+		if len(name) == 0 || start < 0 {
+			continue
+		}
+		value := requestPath[start:end]
+		if bound, ok := variables[name]; ok && bound != value {
+			return nil, false
+		}
+		variables[name] = value
+	}
+	if len(t.suffix) > 0 {
+		variables[SuffixVariableName] = t.suffix
+	}
+	return variables, true
+}
+
+// Rewrite matches a request path and expands a valid path template rewrite with the values
+// of the variables of the template, as envoy rewrites the path of a matched request.
+// Example: /api/{v}/{path=**} rewrites /api/v1/a/b with /{v}/{path} to /v1/a/b
+func (t *Template) Rewrite(requestPath, rewrite string) (string, bool) {
+	variables, ok := t.Match(requestPath)
+	if !ok {
+		return "", false
+	}
+	return expandRewrite(rewrite, variables), true
+}
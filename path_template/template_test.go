@@ -0,0 +1,183 @@
+package path_template
+
+import (
+	"errors"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestParse(t *testing.T) {
+	tmpl, err := Parse("/users/{id}")
+	assert.NilError(t, err)
+	assert.Equal(t, tmpl.String(), "/users/{id}")
+
+	_, err = Parse("/users/{id}/{id}")
+	assert.Error(t, err, "Variable name is duplicated: id")
+
+	_, err = Parse("/users/{id}/{id}", WithDialect(DialectExtended))
+	assert.NilError(t, err)
+}
+
+func TestCompileTemplateRegexpInternalError(t *testing.T) {
+	// variable names of valid templates are valid group names
+	_, err := compileTemplateRegexp(&ParsedTemplate{
+		Text:     "/{a-b}",
+		Segments: []Node{{Kind: NodeVariable, Text: "{a-b}", Name: "a-b"}},
+	})
+	var internalErr *InternalError
+	assert.Assert(t, errors.As(err, &internalErr))
+	assert.Equal(t, internalErr.Invariant, InvariantTemplateRegexp)
+	assert.Equal(t, internalErr.Template, "/{a-b}")
+}
+
+func TestTemplateMatch(t *testing.T) {
+	tt := []struct {
+		template    string
+		opts        []Option
+		requestPath string
+		match       bool
+		variables   map[string]string
+	}{
+		{template: "/api/v1", requestPath: "/api/v1", match: true, variables: map[string]string{}},
+		{template: "/api/v1", requestPath: "/api/v2"},
+		// literals are not regular expressions
+		{template: "/a.b/c+d", requestPath: "/aXb/ccd"},
+		{template: "/users/{id}/profile", requestPath: "/users/42/profile", match: true, variables: map[string]string{"id": "42"}},
+		{template: "/users/{id}", requestPath: "/users/42/profile"},
+		{template: "/users/{id}/profile", requestPath: "/users//profile"},
+		{template: "/a/{b=c/*}/d", requestPath: "/a/c/x/d", match: true, variables: map[string]string{"b": "c/x"}},
+		{template: "/static/{path=**}", requestPath: "/static/css/site.css", match: true, variables: map[string]string{"path": "css/site.css"}},
+		{template: "/static/{path=**}", requestPath: "/static/", match: true, variables: map[string]string{"path": ""}},
+		{template: "/static/**", requestPath: "/static"},
+		{template: "/videos/{path=**}.m3u8", requestPath: "/videos/a/b.m3u8", match: true, variables: map[string]string{"path": "a/b"}},
+		{template: "/videos/*.m3u8", requestPath: "/videos/a"},
+		{template: "/", requestPath: "/", match: true, variables: map[string]string{}},
+		{template: "/a/", requestPath: "/a"},
+		// the query string and the fragment are not matched
+		{template: "/users/{id}", requestPath: "/users/42?x=1#top", match: true, variables: map[string]string{"id": "42"}},
+		// values are not decoded
+		{template: "/users/{id}", requestPath: "/users/a%20b", match: true, variables: map[string]string{"id": "a%20b"}},
+		{template: "/users/{id}", requestPath: "/users/a b"},
+		{
+			template:    "/videos/{path=**}.m3u8",
+			opts:        []Option{WithSuffixVariable()},
+			requestPath: "/videos/a.m3u8",
+			match:       true,
+			variables:   map[string]string{"path": "a", SuffixVariableName: ".m3u8"},
+		},
+		{
+			template:    "/{a}-v1/{b}.ts",
+			opts:        []Option{WithMultipleSuffixes()},
+			requestPath: "/x-v1/y.ts",
+			match:       true,
+			variables:   map[string]string{"a": "x", "b": "y"},
+		},
+		{
+			template:    "/v{major}/img-*.png",
+			opts:        []Option{WithOperatorPrefixes()},
+			requestPath: "/v2/img-cat.png",
+			match:       true,
+			variables:   map[string]string{"major": "2"},
+		},
+		{template: "/v{major}", opts: []Option{WithOperatorPrefixes()}, requestPath: "/v"},
+		{
+			template:    "/{id}/compare/{id}",
+			opts:        []Option{WithVariableReuse()},
+			requestPath: "/42/compare/42",
+			match:       true,
+			variables:   map[string]string{"id": "42"},
+		},
+		{template: "/{id}/compare/{id}", opts: []Option{WithVariableReuse()}, requestPath: "/42/compare/43"},
+		{
+			template:    "/users/{id}[/{format}]",
+			opts:        []Option{WithOptionalSegments()},
+			requestPath: "/users/42",
+			match:       true,
+			variables:   map[string]string{"id": "42"},
+		},
+		{
+			template:    "/users/{id}[/{format}]",
+			opts:        []Option{WithOptionalSegments()},
+			requestPath: "/users/42/json",
+			match:       true,
+			variables:   map[string]string{"id": "42", "format": "json"},
+		},
+		{template: "/users/{id}[/{format}]", opts: []Option{WithOptionalSegments()}, requestPath: "/users/42/"},
+	}
+	for _, tc := range tt {
+		tmpl, err := Parse(tc.template, tc.opts...)
+		assert.NilError(t, err, tc.template)
+		variables, match := tmpl.Match(tc.requestPath)
+		assert.Equal(t, match, tc.match, "%s %s", tc.template, tc.requestPath)
+		assert.DeepEqual(t, variables, tc.variables)
+	}
+}
+
+func TestTemplateRewrite(t *testing.T) {
+	tt := []struct {
+		template    string
+		opts        []Option
+		requestPath string
+		rewrite     string
+		match       bool
+		expected    string
+	}{
+		{template: "/api/{v}/{path=**}", requestPath: "/api/v1/a/b", rewrite: "/{v}/{path}", match: true, expected: "/v1/a/b"},
+		{template: "/api/{v}/{path=**}", requestPath: "/other/v1", rewrite: "/{v}/{path}"},
+		{
+			template:    "/videos/{path=**}.m3u8",
+			opts:        []Option{WithSuffixVariable()},
+			requestPath: "/videos/a/b.m3u8",
+			rewrite:     "/{path}{__suffix}",
+			match:       true,
+			expected:    "/a/b.m3u8",
+		},
+	}
+	for _, tc := range tt {
+		tmpl, err := Parse(tc.template, tc.opts...)
+		assert.NilError(t, err, tc.template)
+		rewritten, match := tmpl.Rewrite(tc.requestPath, tc.rewrite)
+		assert.Equal(t, match, tc.match, "%s %s", tc.template, tc.requestPath)
+		assert.Equal(t, rewritten, tc.expected)
+	}
+}
+
+func TestTemplateMatchBytes(t *testing.T) {
+	tmpl, err := Parse("/users/{id}/{path=**}")
+	assert.NilError(t, err)
+
+	requestPath := []byte("/users/42/a/b?x=1")
+	variables, match := tmpl.MatchBytes(requestPath)
+	assert.Assert(t, match)
+	// values don't share memory with the request path
+	copy(requestPath, "/users/43/c/d")
+	assert.DeepEqual(t, variables, map[string]string{"id": "42", "path": "a/b"})
+
+	_, match = tmpl.MatchBytes([]byte("/groups/42"))
+	assert.Assert(t, !match)
+
+	// no allocations beyond those of matching a copy
+	for _, requestPath := range [][]byte{[]byte("/users/42/a/b"), []byte("/groups/42")} {
+		matchBytes := testing.AllocsPerRun(100, func() { tmpl.MatchBytes(requestPath) })
+		matchCopy := testing.AllocsPerRun(100, func() { tmpl.Match(string(requestPath)) })
+		assert.Assert(t, matchBytes <= matchCopy, "%s: %v > %v", requestPath, matchBytes, matchCopy)
+	}
+}
+
+// TestTemplateMatchAutomaton checks that Match agrees with the automaton the template relations are built on
+func TestTemplateMatchAutomaton(t *testing.T) {
+	templates := []string{"/a/{b=c/*}/**.m3u8", "/*/x/", "/{a}-v1", "/", "/**"}
+	paths := []string{"/", "/a", "/a/", "/a/c/x/y.m3u8", "/a/c/x/.m3u8", "/q/x/", "/q/x", "/-v1", "/z-v1", "/a/b/c"}
+	for _, template := range templates {
+		tmpl, err := Parse(template)
+		assert.NilError(t, err)
+		parsed, err := parseValid(template, nil)
+		assert.NilError(t, err)
+		automaton := templateAutomaton(parsed)
+		for _, path := range paths {
+			_, match := tmpl.Match(path)
+			assert.Equal(t, match, automaton.matches(path), "%s %s", template, path)
+		}
+	}
+}
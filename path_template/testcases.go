@@ -0,0 +1,144 @@
+package path_template
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// maxTestCaseDepth is the number of segments a text glob expands to in deep test cases
+const maxTestCaseDepth = 3
+
+// TestCase is a sample path for a path template, along with whether the template matches it
+// and the values it binds to the variables of the template when it does
+type TestCase struct {
+	// Name describes what the test case exercises
+	Name string
+	Path string
+	// Match is true for positive test cases
+	Match bool
+	// Variables holds the expected variable bindings of positive test cases, nil for negative ones
+	Variables map[string]string
+}
+
+// GenerateTestCases returns positive and negative sample paths for a path template, covering
+// boundary cases such as empty and deep text globs, empty path globs, suffixes and trailing slashes.
+// Downstream teams can commit them as golden tests for their routes. Every test case is checked
+// against the template automaton, so a negative test case is never matched by the template.
+func GenerateTestCases(template string) ([]TestCase, error) {
+	parsed, err := parseValid(template, nil)
+	if err != nil {
+		return nil, err
+	}
+	tokens, suffix := flattenTokens(parsed)
+	automaton := templateAutomaton(parsed)
+
+	literals := []string{}
+	for _, t := range tokens {
+		if t.kind == tokenLiteral {
+			literals = append(literals, t.literal)
+		}
+	}
+	// fresh values are distinct from the literals, so bindings are never ambiguous
+	values := 0
+	fresh := func() string {
+		values++
+		value := "v" + strconv.Itoa(values)
+		for slices.Contains(literals, value) || slices.ContainsFunc(tokens, func(t token) bool { return strings.Contains(t.suffix, value) }) {
+			value += "x"
+		}
+		return value
+	}
+
+	// typical holds the text matched by each token in the typical path
+	typical := make([]string, len(tokens))
+	for i, t := range tokens {
+		switch t.kind {
+		case tokenLiteral:
+			typical[i] = t.literal
+		default:
+			typical[i] = fresh()
+		}
+	}
+
+	cases := []TestCase{}
+	add := func(name string, parts []string, pathSuffix string, match bool) {
+		path := joinTokenTexts(tokens, parts, pathSuffix)
+		if automaton.matches(path) != match || slices.ContainsFunc(cases, func(tc TestCase) bool { return tc.Path == path }) {
+			return
+		}
+		tc := TestCase{Name: name, Path: path, Match: match}
+		if match {
+			tc.Variables = testCaseBindings(tokens, parts)
+		}
+		cases = append(cases, tc)
+	}
+	// with replaces the text of the tokens of a kind
+	with := func(kind tokenKind, text func() string) []string {
+		parts := slices.Clone(typical)
+		for i, t := range tokens {
+			if t.kind == kind {
+				parts[i] = text()
+			}
+		}
+		return parts
+	}
+
+	add("typical", typical, suffix, true)
+	add("empty text glob", with(tokenTextGlob, func() string { return "" }), suffix, true)
+	add("deep text glob", with(tokenTextGlob, func() string {
+		segments := make([]string, maxTestCaseDepth)
+		for i := range segments {
+			segments[i] = fresh()
+		}
+		return strings.Join(segments, "/")
+	}), suffix, true)
+	if len(suffix) > 0 && tokens[len(tokens)-1].kind != tokenLiteral {
+		parts := slices.Clone(typical)
+		parts[len(parts)-1] += suffix
+		add("value ending with the suffix", parts, suffix, true)
+	}
+
+	add("empty path glob", with(tokenPathGlob, func() string { return "" }), suffix, false)
+	if len(suffix) > 0 {
+		add("missing suffix", typical, "", false)
+	}
+	if len(typical) > 1 {
+		add("missing last segment", typical[:len(typical)-1], suffix, false)
+	}
+	if last := len(typical) - 1; len(typical[last]) == 0 {
+		// / and /a/ end with an empty segment
+		add("extra segment", append(slices.Clone(typical[:last]), fresh()), suffix, false)
+		add("missing trailing slash", typical[:last], suffix, false)
+	} else {
+		add("extra segment", append(slices.Clone(typical), fresh()), suffix, false)
+		add("extra trailing slash", typical, suffix+"/", false)
+	}
+	for i, t := range tokens {
+		if t.kind == tokenLiteral && len(t.literal) > 0 {
+			parts := slices.Clone(typical)
+			parts[i] = fresh()
+			add("different literal", parts, suffix, false)
+			break
+		}
+	}
+
+	return cases, nil
+}
+
+// testCaseBindings returns the values bound to the variables of the tokens, a variable spanning
+// several tokens binds their text joined by slashes
+func testCaseBindings(tokens []token, parts []string) map[string]string {
+	bindings := map[string]string{}
+	for i, t := range tokens {
+		if len(t.variable) == 0 {
+			continue
+		}
+		if value, ok := bindings[t.variable]; ok {
+			bindings[t.variable] = value + "/" + parts[i]
+		} else {
+			bindings[t.variable] = parts[i]
+		}
+	}
+	return bindings
+}
@@ -0,0 +1,59 @@
+package path_template
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestGenerateTestCases(t *testing.T) {
+	cases, err := GenerateTestCases("/a/{id}/b")
+	assert.NilError(t, err)
+	assert.DeepEqual(t, cases, []TestCase{
+		{Name: "typical", Path: "/a/v1/b", Match: true, Variables: map[string]string{"id": "v1"}},
+		{Name: "empty path glob", Path: "/a//b"},
+		{Name: "missing last segment", Path: "/a/v1"},
+		{Name: "extra segment", Path: "/a/v1/b/v2"},
+		{Name: "extra trailing slash", Path: "/a/v1/b/"},
+		{Name: "different literal", Path: "/v3/v1/b"},
+	})
+
+	cases, err = GenerateTestCases("/static/{path=**}")
+	assert.NilError(t, err)
+	assert.DeepEqual(t, cases, []TestCase{
+		{Name: "typical", Path: "/static/v1", Match: true, Variables: map[string]string{"path": "v1"}},
+		{Name: "empty text glob", Path: "/static/", Match: true, Variables: map[string]string{"path": ""}},
+		{Name: "deep text glob", Path: "/static/v2/v3/v4", Match: true, Variables: map[string]string{"path": "v2/v3/v4"}},
+		{Name: "missing last segment", Path: "/static"},
+		{Name: "different literal", Path: "/v6/v1"},
+	})
+
+	cases, err = GenerateTestCases("/{f}.m3u8")
+	assert.NilError(t, err)
+	assert.DeepEqual(t, cases, []TestCase{
+		{Name: "typical", Path: "/v1.m3u8", Match: true, Variables: map[string]string{"f": "v1"}},
+		{Name: "value ending with the suffix", Path: "/v1.m3u8.m3u8", Match: true, Variables: map[string]string{"f": "v1.m3u8"}},
+		{Name: "empty path glob", Path: "/.m3u8"},
+		{Name: "missing suffix", Path: "/v1"},
+		{Name: "extra segment", Path: "/v1/v2.m3u8"},
+		{Name: "extra trailing slash", Path: "/v1.m3u8/"},
+	})
+
+	_, err = GenerateTestCases("/{id}/{id}")
+	assert.ErrorContains(t, err, "Variable name is duplicated: id")
+}
+
+func TestGenerateTestCasesAgreeWithTemplate(t *testing.T) {
+	for _, template := range []string{"/", "/a/", "/{x=a/*}/**.ts", "/v1/{v2}/**", "/{a}/{b=**}"} {
+		cases, err := GenerateTestCases(template)
+		assert.NilError(t, err)
+		assert.Assert(t, len(cases) > 1, template)
+
+		parsed, err := parseValid(template, nil)
+		assert.NilError(t, err)
+		automaton := templateAutomaton(parsed)
+		for _, tc := range cases {
+			assert.Equal(t, automaton.matches(tc.Path), tc.Match, "%s %s", template, tc.Path)
+		}
+	}
+}
@@ -0,0 +1,246 @@
+package path_template
+
+import (
+	"fmt"
+	"net"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// URLComponent identifies a component of a URL template
+type URLComponent string
+
+const (
+	URLComponentScheme URLComponent = "scheme"
+	URLComponentHost   URLComponent = "host"
+	URLComponentPath   URLComponent = "path"
+	URLComponentQuery  URLComponent = "query"
+)
+
+var (
+	// scheme = ALPHA *( ALPHA / DIGIT / "+" / "-" / "." ) per RFC 3986
+	reURLScheme = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9+\-.]*$`)
+
+	// host literals are restricted to what can appear in a DNS name or an IPv4 address
+	reURLHostLiteral = regexp.MustCompile(`^[a-zA-Z0-9\-.]*$`)
+
+	reURLPort = regexp.MustCompile(`^[0-9]+$`)
+
+	// query keys and values are pchars plus / and ?, excluding the & and = delimiters
+	reURLQueryLiteral = regexp.MustCompile(`^[a-zA-Z0-9-._~%!$'()*+,;:@/?]+$`)
+)
+
+// URLComponentError is the validation error of a single URL template component
+type URLComponentError struct {
+	Component URLComponent
+	Err       error
+}
+
+func (e *URLComponentError) Error() string {
+	return fmt.Sprintf("Invalid %s in URL template: %s", e.Component, e.Err)
+}
+
+func (e *URLComponentError) Unwrap() error {
+	return e.Err
+}
+
+// URLTemplateError holds the errors of every invalid component of a URL template,
+// in the order the components appear in the URL
+type URLTemplateError struct {
+	Components []*URLComponentError
+	// prefix of every component message, see WithErrorPrefix
+	prefix string
+}
+
+func (e *URLTemplateError) Error() string {
+	messages := make([]string, 0, len(e.Components))
+	for _, c := range e.Components {
+		if len(e.prefix) > 0 {
+			messages = append(messages, e.prefix+": "+c.Error())
+			continue
+		}
+		messages = append(messages, c.Error())
+	}
+	return strings.Join(messages, "\n")
+}
+
+func (e *URLTemplateError) Unwrap() []error {
+	errs := make([]error, 0, len(e.Components))
+	for _, c := range e.Components {
+		errs = append(errs, c)
+	}
+	return errs
+}
+
+// ValidateURLTemplate validates a full URL template - scheme://host/path?query
+// ie https://{tenant}.example.com/api/{v}/**?mode=*
+// The scheme is a plain literal, the host may contain {name} variables and a port,
+// the path is a path template and query values may be literals, * or {name} variables.
+// Every component is validated, so the returned *URLTemplateError reports all invalid components.
+// On success the variable names of all components are returned, in order of appearance.
+func ValidateURLTemplate(urlTemplate string, opts ...Option) ([]string, error) {
+	o := newOptions(opts)
+	variableNames, err := validateURLTemplate(urlTemplate, o)
+	return variableNames, o.reportError(err)
+}
+
+func validateURLTemplate(urlTemplate string, o *options) ([]string, error) {
+	scheme, rest, found := strings.Cut(urlTemplate, "://")
+	if !found {
+		return nil, fmt.Errorf("URL template must contain a scheme: %s", excerpt(urlTemplate))
+	}
+
+	rest, query, hasQuery := strings.Cut(rest, "?")
+	host, path := rest, "/"
+	if i := strings.IndexRune(rest, '/'); i >= 0 {
+		host, path = rest[:i], rest[i:]
+	}
+
+	urlErr := &URLTemplateError{}
+	addErr := func(component URLComponent, err error) {
+		urlErr.Components = append(urlErr.Components, &URLComponentError{Component: component, Err: err})
+	}
+
+	if !reURLScheme.MatchString(scheme) {
+		addErr(URLComponentScheme, fmt.Errorf("Scheme must start with a letter and contain only letters, digits, +, - and .: %s", excerpt(scheme)))
+	}
+
+	variableNames := []string{}
+	hostVariables, err := validateURLHostTemplate(host, o)
+	if err != nil {
+		addErr(URLComponentHost, err)
+	}
+	variableNames = append(variableNames, hostVariables...)
+
+	end := o.startParse(path)
+	pathVariables, err := validatePathTemplate(path, o)
+	end(err)
+	if err != nil {
+		addErr(URLComponentPath, err)
+	}
+	for _, name := range pathVariables {
+		if slices.Contains(variableNames, name) {
+			addErr(URLComponentPath, fmt.Errorf("Variable name is duplicated: %s", excerpt(name)))
+			continue
+		}
+		variableNames = append(variableNames, name)
+	}
+
+	if hasQuery {
+		queryVariables, err := validateURLQueryTemplate(query, variableNames, o)
+		if err != nil {
+			addErr(URLComponentQuery, err)
+		}
+		variableNames = append(variableNames, queryVariables...)
+	}
+
+	if len(urlErr.Components) > 0 {
+		return nil, urlErr
+	}
+	return variableNames, nil
+}
+
+// validateURLHostTemplate validates host[:port] where host may contain {name} variables
+func validateURLHostTemplate(hostTemplate string, o *options) ([]string, error) {
+	variableNames, err := scanHostTemplate(hostTemplate, o)
+	if err != nil {
+		return nil, err
+	}
+	for i, name := range variableNames {
+		if slices.Contains(variableNames[:i], name) {
+			return nil, fmt.Errorf("Variable name is duplicated: %s", excerpt(name))
+		}
+	}
+	return variableNames, nil
+}
+
+// scanHostTemplate validates the syntax of host[:port] where host may contain {name} variables
+// or be an IPv6 address in brackets
+// and returns every variable reference, in order of appearance
+func scanHostTemplate(hostTemplate string, o *options) ([]string, error) {
+	host := hostTemplate
+	if strings.HasPrefix(hostTemplate, "[") || strings.ContainsRune(hostTemplate, ':') {
+		// host:port and [ipv6] with an optional port, following net.SplitHostPort
+		h, port, err := net.SplitHostPort(hostTemplate)
+		hasPort := true
+		if err != nil && strings.HasPrefix(hostTemplate, "[") && strings.HasSuffix(hostTemplate, "]") {
+			h, hasPort, err = hostTemplate[1:len(hostTemplate)-1], false, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("Invalid host: %s", excerpt(hostTemplate))
+		}
+		if hasPort && !reURLPort.MatchString(port) {
+			return nil, fmt.Errorf("Port must be numeric: %s", excerpt(port))
+		}
+		host = h
+		// only IPv6 addresses contain colons, they can't contain variables
+		if strings.ContainsRune(host, ':') {
+			if net.ParseIP(host) == nil {
+				return nil, fmt.Errorf("Invalid IPv6 address in host: %s", excerpt(hostTemplate))
+			}
+			return []string{}, nil
+		}
+	}
+	if len(host) == 0 {
+		return nil, fmt.Errorf("Host cannot be empty")
+	}
+
+	variableNames := []string{}
+	for len(host) > 0 {
+		start := strings.IndexRune(host, '{')
+		if start < 0 {
+			start = len(host)
+		}
+		if literal := host[:start]; !reURLHostLiteral.MatchString(literal) {
+			return nil, fmt.Errorf("Invalid character in host: %s", excerpt(hostTemplate))
+		}
+		if start == len(host) {
+			break
+		}
+
+		end := strings.IndexRune(host[start:], '}')
+		if end < 0 {
+			return nil, fmt.Errorf("Unmatched { not allowed in host: %s", excerpt(hostTemplate))
+		}
+		name := host[start+1 : start+end]
+		if err := validateVariableName(name, hostTemplate, o.limits); err != nil {
+			return nil, err
+		}
+		variableNames = append(variableNames, name)
+		host = host[start+end+1:]
+	}
+	return variableNames, nil
+}
+
+// validateURLQueryTemplate validates key=value pairs separated by &.
+// Values may be literals, * (any value) or a {name} variable
+func validateURLQueryTemplate(query string, knownVariables []string, o *options) ([]string, error) {
+	variableNames := []string{}
+	for _, pair := range strings.Split(query, "&") {
+		key, value, _ := strings.Cut(pair, "=")
+		if !reURLQueryLiteral.MatchString(key) || strings.ContainsRune(key, '*') {
+			return nil, fmt.Errorf("Invalid query parameter name: %s", excerpt(pair))
+		}
+
+		switch {
+		// flag, flag=, flag=* or flag=value
+		case len(value) == 0 || value == textGlob:
+			continue
+		case reURLQueryLiteral.MatchString(value) && !strings.ContainsRune(value, '*'):
+			continue
+		case len(value) > 1 && value[0] == '{' && value[len(value)-1] == '}':
+			name := value[1 : len(value)-1]
+			if err := validateVariableName(name, query, o.limits); err != nil {
+				return nil, err
+			}
+			if slices.Contains(knownVariables, name) || slices.Contains(variableNames, name) {
+				return nil, fmt.Errorf("Variable name is duplicated: %s", excerpt(name))
+			}
+			variableNames = append(variableNames, name)
+		default:
+			return nil, fmt.Errorf("Invalid query parameter value: %s", excerpt(pair))
+		}
+	}
+	return variableNames, nil
+}
@@ -0,0 +1,161 @@
+package path_template
+
+import (
+	"errors"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestURLTemplateSuccess(t *testing.T) {
+	tt := []struct {
+		url       string
+		variables []string
+	}{
+		{
+			url:       "https://{tenant}.example.com/api/{v}/**?mode=*",
+			variables: []string{"tenant", "v"},
+		},
+		{
+			url:       "http://example.com",
+			variables: []string{},
+		},
+		{
+			url:       "http://localhost:8080/",
+			variables: []string{},
+		},
+		{
+			url:       "http://[::1]:8080/{a}",
+			variables: []string{"a"},
+		},
+		{
+			url:       "http://[2001:db8::1]/",
+			variables: []string{},
+		},
+		{
+			url:       "grpc+tls://{svc}-{region}.internal:443/{method=**}",
+			variables: []string{"svc", "region", "method"},
+		},
+		{
+			url:       "https://example.com/hooks/{id}?token={token}&flag&v=1",
+			variables: []string{"id", "token"},
+		},
+	}
+	for _, tc := range tt {
+		variables, err := ValidateURLTemplate(tc.url)
+		assert.NilError(t, err, tc.url)
+		assert.DeepEqual(t, variables, tc.variables)
+	}
+}
+
+func TestURLTemplateFailure(t *testing.T) {
+	tt := []struct {
+		url string
+		err string
+	}{
+		{
+			url: "example.com/a",
+			err: "URL template must contain a scheme: example.com/a",
+		},
+		{
+			url: "1http://example.com",
+			err: "Invalid scheme in URL template: Scheme must start with a letter and contain only letters, digits, +, - and .: 1http",
+		},
+		{
+			url: "http:///a",
+			err: "Invalid host in URL template: Host cannot be empty",
+		},
+		{
+			url: "http://:8080/a",
+			err: "Invalid host in URL template: Host cannot be empty",
+		},
+		{
+			url: "http://[]:8080/a",
+			err: "Invalid host in URL template: Host cannot be empty",
+		},
+		{
+			url: "http://::1/a",
+			err: "Invalid host in URL template: Invalid host: ::1",
+		},
+		{
+			url: "http://[::1/a",
+			err: "Invalid host in URL template: Invalid host: [::1",
+		},
+		{
+			url: "http://[::g]:80/a",
+			err: "Invalid host in URL template: Invalid IPv6 address in host: [::g]:80",
+		},
+		{
+			url: "http://[::1]:port/a",
+			err: "Invalid host in URL template: Port must be numeric: port",
+		},
+		{
+			url: "http://example.com:port",
+			err: "Invalid host in URL template: Port must be numeric: port",
+		},
+		{
+			url: "http://{tenant.example.com",
+			err: "Invalid host in URL template: Unmatched { not allowed in host: {tenant.example.com",
+		},
+		{
+			url: "http://exa_mple.com",
+			err: "Invalid host in URL template: Invalid character in host: exa_mple.com",
+		},
+		{
+			url: "http://{a}.{a}.com",
+			err: "Invalid host in URL template: Variable name is duplicated: a",
+		},
+		{
+			url: "http://{tenant}.example.com/{tenant}",
+			err: "Invalid path in URL template: Variable name is duplicated: tenant",
+		},
+		{
+			url: "http://example.com/a?b={c=*}",
+			err: "Invalid query in URL template: Variable name must start with a letter and contain only alphanumeric characters and underscores: c=*",
+		},
+		{
+			url: "http://example.com/a?b=x*",
+			err: "Invalid query in URL template: Invalid query parameter value: b=x*",
+		},
+		{
+			url: "http://example.com/a?=b",
+			err: "Invalid query in URL template: Invalid query parameter name: =b",
+		},
+		{
+			url: "1http://{a/b/**/*?b=x*",
+			err: "Invalid scheme in URL template: Scheme must start with a letter and contain only letters, digits, +, - and .: 1http\n" +
+				"Invalid host in URL template: Unmatched { not allowed in host: {a\n" +
+				"Invalid path in URL template: Cannot have path glob (*) after text glob (**)\n" +
+				"Invalid query in URL template: Invalid query parameter value: b=x*",
+		},
+	}
+	for _, tc := range tt {
+		_, err := ValidateURLTemplate(tc.url)
+		assert.Error(t, err, tc.err)
+	}
+}
+
+func TestURLTemplateComponents(t *testing.T) {
+	_, err := ValidateURLTemplate("ftp://{a}.com/**/*?q=*")
+
+	var urlErr *URLTemplateError
+	assert.Assert(t, errors.As(err, &urlErr))
+	assert.Equal(t, len(urlErr.Components), 1)
+	assert.Equal(t, urlErr.Components[0].Component, URLComponentPath)
+	assert.Error(t, urlErr.Components[0].Err, "Cannot have path glob (*) after text glob (**)")
+}
+
+func TestURLTemplateErrorPrefix(t *testing.T) {
+	prefix := WithErrorPrefix("spec.url")
+
+	_, err := ValidateURLTemplate("http://exa_mple.com/{a}/{a}?b=x*", prefix)
+	assert.Error(t, err, "spec.url: Invalid host in URL template: Invalid character in host: exa_mple.com\n"+
+		"spec.url: Invalid path in URL template: Variable name is duplicated: a\n"+
+		"spec.url: Invalid query in URL template: Invalid query parameter value: b=x*")
+	var urlErr *URLTemplateError
+	assert.Assert(t, errors.As(err, &urlErr))
+	assert.Equal(t, len(urlErr.Components), 3)
+
+	_, err = ValidateURLTemplate("example.com/a", prefix)
+	assert.Error(t, err, "spec.url: URL template must contain a scheme: example.com/a")
+}
@@ -0,0 +1,147 @@
+package path_template
+
+import (
+	"maps"
+	"slices"
+)
+
+// Validator validates a path template as it is being edited, ie on every keystroke of an editor
+// or language server integration. Options are resolved once and each Update validates again only
+// the segments that changed: a segment validated without error is reused when its text and
+// everything it depends on, the variables and operators before it, are unchanged.
+// Diagnostics are the same as those of ParseLenient.
+// A Validator is not safe for concurrent use.
+type Validator struct {
+	o           *options
+	text        string
+	validated   bool
+	parsed      *ParsedTemplate
+	diagnostics []Diagnostic
+	cache       segmentCache
+}
+
+// NewValidator creates a Validator validating with opts
+func NewValidator(opts ...Option) *Validator {
+	return &Validator{o: newOptions(opts)}
+}
+
+// Update sets the current text and returns its diagnostics
+func (v *Validator) Update(text string) []Diagnostic {
+	if v.validated && text == v.text {
+		return v.diagnostics
+	}
+	v.text = text
+	v.parsed, v.diagnostics = parseLenientCached(text, v.o, &v.cache)
+	v.diagnostics = v.o.reportDiagnostics(v.diagnostics)
+	v.validated = true
+	return v.diagnostics
+}
+
+// Text returns the current text
+func (v *Validator) Text() string {
+	return v.text
+}
+
+// Parsed returns the best-effort parse of the current text, nil before the first Update
+func (v *Validator) Parsed() *ParsedTemplate {
+	return v.parsed
+}
+
+// Diagnostics returns the diagnostics of the current text
+func (v *Validator) Diagnostics() []Diagnostic {
+	return v.diagnostics
+}
+
+// validatorState is what the validation of a segment depends on and changes
+type validatorState struct {
+	foundTextGlob    bool
+	foundSuffix      bool
+	variableNames    []string
+	variablePatterns map[string]string
+}
+
+func (s validatorState) equal(v *pathTemplateValidator) bool {
+	return s.foundTextGlob == v.foundTextGlob && s.foundSuffix == v.foundSuffix &&
+		slices.Equal(s.variableNames, v.variableNames) && maps.Equal(s.variablePatterns, v.variablePatterns)
+}
+
+// restore sets the state of v to s
+func (v *pathTemplateValidator) restore(s validatorState) {
+	v.foundTextGlob = s.foundTextGlob
+	v.foundSuffix = s.foundSuffix
+	v.variableNames = slices.Clone(s.variableNames)
+	v.variablePatterns = maps.Clone(s.variablePatterns)
+}
+
+// cachedSegment is a segment validated without error
+type cachedSegment struct {
+	text          string
+	before, after validatorState
+	node          Node
+}
+
+// segmentCache holds the segments of the previous text, matched with the segments of the current
+// text by their position from the start and from the end, so edits anywhere reuse the others.
+// A nil cache caches nothing.
+type segmentCache struct {
+	previous []*cachedSegment
+	current  []*cachedSegment
+	// validated counts the segments validated rather than reused, for tests
+	validated int
+}
+
+// start starts caching the segments of a text with count segments
+func (c *segmentCache) start(count int) {
+	if c == nil {
+		return
+	}
+	c.current = make([]*cachedSegment, count)
+	c.validated = 0
+}
+
+// lookup returns the cached segment i of the current text, validated from the state of v
+func (c *segmentCache) lookup(i int, text string, v *pathTemplateValidator) (*cachedSegment, bool) {
+	if c == nil {
+		return nil, false
+	}
+	for _, j := range []int{i, len(c.previous) - (len(c.current) - i)} {
+		if j < 0 || j >= len(c.previous) || c.previous[j] == nil {
+			continue
+		}
+		if cached := c.previous[j]; cached.text == text && cached.before.equal(v) {
+			c.current[i] = cached
+			return cached, true
+		}
+	}
+	c.validated++
+	return nil, false
+}
+
+// snapshot returns the state of v before validating a segment, when caching
+func (c *segmentCache) snapshot(v *pathTemplateValidator) validatorState {
+	if c == nil {
+		return validatorState{}
+	}
+	return validatorState{
+		foundTextGlob:    v.foundTextGlob,
+		foundSuffix:      v.foundSuffix,
+		variableNames:    slices.Clone(v.variableNames),
+		variablePatterns: maps.Clone(v.variablePatterns),
+	}
+}
+
+// store caches the segment i, validated without error from before
+func (c *segmentCache) store(i int, before validatorState, v *pathTemplateValidator, node Node) {
+	if c == nil {
+		return
+	}
+	c.current[i] = &cachedSegment{text: node.Text, before: before, after: c.snapshot(v), node: node}
+}
+
+// finish makes the segments of the current text those of the previous one
+func (c *segmentCache) finish() {
+	if c == nil {
+		return
+	}
+	c.previous, c.current = c.current, nil
+}
@@ -0,0 +1,112 @@
+package path_template
+
+import (
+	"math/rand/v2"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestValidatorUpdate(t *testing.T) {
+	v := NewValidator()
+	assert.Assert(t, v.Parsed() == nil)
+
+	// typing /api/{id} one keystroke at a time
+	steps := []struct {
+		text        string
+		diagnostics []Diagnostic
+	}{
+		{text: "/"},
+		{text: "/a"},
+		{text: "/ap"},
+		{text: "/api"},
+		{text: "/api/"},
+		{
+			text: "/api/{",
+			diagnostics: []Diagnostic{
+				{Range: Range{Start: 5, End: 6}, Message: "Unmatched { not allowed in path template: api/{"},
+				{Range: Range{Start: 5, End: 6}, Message: "Invalid segment in path template: {"},
+			},
+		},
+		{
+			text: "/api/{}",
+			diagnostics: []Diagnostic{
+				{Range: Range{Start: 5, End: 7}, Message: "Variable name cannot be empty: /api/{}"},
+			},
+		},
+		{text: "/api/{id}"},
+	}
+	for _, step := range steps {
+		diagnostics := v.Update(step.text)
+		if step.diagnostics == nil {
+			step.diagnostics = []Diagnostic{}
+		}
+		assert.DeepEqual(t, diagnostics, step.diagnostics)
+		assert.DeepEqual(t, v.Diagnostics(), step.diagnostics)
+		assert.Equal(t, v.Text(), step.text)
+	}
+	assert.DeepEqual(t, v.Parsed().Variables, []string{"id"})
+
+	// unchanged text keeps the previous results
+	parsed := v.Parsed()
+	v.Update("/api/{id}")
+	assert.Assert(t, parsed == v.Parsed())
+}
+
+func TestValidatorOptions(t *testing.T) {
+	v := NewValidator(WithStrictRFC3986())
+	diagnostics := v.Update("/a=b")
+	assert.DeepEqual(t, diagnostics, []Diagnostic{
+		{Range: Range{Start: 1, End: 4}, Message: "Bare = not allowed in literal in strict RFC 3986 mode: a=b"},
+	})
+}
+
+func TestValidatorIncremental(t *testing.T) {
+	v := NewValidator()
+	steps := []struct {
+		text      string
+		validated int
+	}{
+		{text: "/api/{v}/users/{id}", validated: 4},
+		// typing at the end validates the last segment only
+		{text: "/api/{v}/users/{id}/x", validated: 1},
+		// an edit in the middle reuses the segments after it
+		{text: "/api/{v}/groups/{id}/x", validated: 1},
+		// segments after a changed variable or operator are validated again
+		{text: "/api/{id}/groups/{id}/x", validated: 4},
+		{text: "/api/{v}/groups/{id}/x", validated: 4},
+		{text: "/api/**/groups/{id}/x", validated: 4},
+		// as are segments with errors
+		{text: "/api/**/groups/{id}/y", validated: 2},
+	}
+	for _, step := range steps {
+		v.Update(step.text)
+		assert.Equal(t, v.cache.validated, step.validated, step.text)
+	}
+}
+
+// TestValidatorParseLenient checks on random edits that a Validator agrees with ParseLenient
+func TestValidatorParseLenient(t *testing.T) {
+	pieces := []string{"/", "a", "{", "}", "=", "*", "**", "{a}", "{b=*}", ".m3u8", "x"}
+	r := rand.New(rand.NewPCG(1, 2))
+	for _, opts := range [][]Option{nil, {WithDialect(DialectExtended)}, {WithStrictRFC3986()}} {
+		v := NewValidator(opts...)
+		text := "/"
+		for range 2000 {
+			i := r.IntN(len(text) + 1)
+			if r.IntN(3) == 0 && len(text) > 0 {
+				// delete a byte
+				i = min(i, len(text)-1)
+				text = text[:i] + text[i+1:]
+			} else {
+				text = text[:i] + pieces[r.IntN(len(pieces))] + text[i:]
+			}
+			if len(text) > 40 {
+				text = text[:20]
+			}
+			parsed, diagnostics := ParseLenient(text, opts...)
+			assert.DeepEqual(t, v.Update(text), diagnostics)
+			assert.DeepEqual(t, v.Parsed(), parsed)
+		}
+	}
+}